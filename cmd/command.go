@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+)
+
+type Command string
+const (
+	RENDER  Command = "render"
+	EXTRACT Command = "extract"
+	EXPORT  Command = "export"
+	INSPECT Command = "inspect"
+)
+
+// flags accepted by each command, anything else is reported as an error
+var CommandFlags = map[Command][]string {
+	RENDER:  { "-i", "-f", "-n", "-x", "-d", "-g", "-q", "-c", "-p", "-v", "-l", "-w", "-o", "-j", "-r", "-m", "-s", "-t", "-k" },
+	EXTRACT: { "-i", "-f", "-n", "-x", "-d", "-g", "-q", "-c", "-p", "-v", "-l", "-w" },
+	EXPORT:  { "-i", "-f", "-n", "-x", "-d", "-g", "-q", "-c", "-p", "-v", "-l", "-w", "-o", "-e" },
+	INSPECT: { "-i", "-f", "-n", "-x", "-d", "-g", "-q", "-c", "-p", "-v", "-l", "-w" },
+}
+
+// SplitCommand takes the subcommand from the head of args,
+// falling back to render when args start with a flag
+func SplitCommand(args []string) (Command, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return RENDER, args, nil
+	}
+
+	command := Command(strings.ToLower(args[0]))
+	if _, ok := CommandFlags[command]; !ok {
+		return "", nil, errors.New("unknown command: " + args[0] + ". available commands: render, extract, export, inspect")
+	}
+	return command, args[1:], nil
+}
+
+func (c *Config) commandOrDefault() Command {
+	if c.Command == "" {
+		return RENDER
+	}
+	return c.Command
+}
+
+func (c *Config) allowsFlag(flag string) bool {
+	for _, f := range CommandFlags[c.commandOrDefault()] {
+		if f == flag { return true }
+	}
+	return false
+}
+
+func isKnownFlag(flag string) bool {
+	for _, flags := range CommandFlags {
+		for _, f := range flags {
+			if f == flag { return true }
+		}
+	}
+	return false
+}
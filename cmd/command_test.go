@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommand_Default(t *testing.T) {
+	assert := assert.New(t)
+	args := []string{ "-i", "input.jpg" }
+
+	command, rest, err := SplitCommand(args)
+
+	assert.Nil(err)
+	assert.Equal(RENDER, command)
+	assert.Equal(args, rest)
+}
+
+func TestSplitCommand_Named(t *testing.T) {
+	assert := assert.New(t)
+	args := []string{ "export", "-i", "input.jpg", "-e", "gpl" }
+
+	command, rest, err := SplitCommand(args)
+
+	assert.Nil(err)
+	assert.Equal(EXPORT, command)
+	assert.Equal(args[1:], rest)
+}
+
+func TestSplitCommand_Unknown(t *testing.T) {
+	_, _, err := SplitCommand([]string{ "draw", "-i", "input.jpg" })
+
+	assert.ErrorContains(t, err, "unknown command")
+}
+
+func TestMakeCommandConfig_UnsupportedFlag(t *testing.T) {
+	assert := assert.New(t)
+	args := []string{ "-i", "input.jpg", "-r", "100x100" }
+	flagsPos := FindAllFlags(args)
+
+	got, errs := MakeCommandConfig(INSPECT, args, flagsPos)
+
+	assert.Len(errs, 1)
+	assert.ErrorContains(errs[0], "not supported by inspect")
+	assert.Equal(0, got.OutputWidth)
+}
+
+func TestMakeCommandConfig_VerbosityForAllCommands(t *testing.T) {
+	args := []string{ "-i", "input.jpg", "-v", "quiet" }
+	flagsPos := FindAllFlags(args)
+
+	for command := range CommandFlags {
+		got, errs := MakeCommandConfig(command, args, flagsPos)
+
+		assert.Len(t, errs, 0, string(command))
+		assert.Equal(t, QUIET, got.Verbosity, string(command))
+	}
+}
+
+func TestMakeCommandConfig_LoggingForAllCommands(t *testing.T) {
+	args := []string{ "-i", "input.jpg", "-l", "json", "debug" }
+	flagsPos := FindAllFlags(args)
+
+	for command := range CommandFlags {
+		got, errs := MakeCommandConfig(command, args, flagsPos)
+
+		assert.Len(t, errs, 0, string(command))
+		assert.Equal(t, JSON, got.LogFormat, string(command))
+	}
+}
+
+func TestMakeCommandConfig_UnsupportedEarlyFlag(t *testing.T) {
+	assert := assert.New(t)
+	saved := CommandFlags[INSPECT]
+	CommandFlags[INSPECT] = []string{ "-i" }
+	defer func() { CommandFlags[INSPECT] = saved }()
+	args := []string{ "-i", "input.jpg", "-x", "thumbs" }
+
+	got, errs := MakeCommandConfig(INSPECT, args, FindAllFlags(args))
+
+	assert.Len(errs, 1)
+	assert.ErrorContains(errs[0], "flag -x is not supported by inspect")
+	assert.Len(got.Exclude, 0)
+}
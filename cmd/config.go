@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 )
@@ -13,42 +15,151 @@ const (
 	DEFAULT_COLS = 8
 )
 
+// "-" as input reads image from stdin, as output folder (-o -) writes to stdout
+const STDIO = "-"
+
 var IMAGE_EXTENSIONS = [...]string { ".jpg", ".jpeg", ".png", ".webp" }
 
 type Mode string
 const (
 	GRID 		Mode = "GRID"
 	PALLETE Mode = "PALLETE"
+	QUADTREE Mode = "QUADTREE"
+	PIXELATE Mode = "PIXELATE" // tile colors as sharp blocks, optionally upscaled
+	SPRITE   Mode = "SPRITE"   // true size, every tile is one pixel
+	DITHER   Mode = "DITHER"   // source remapped to few extracted colors
+	POSTERIZE Mode = "POSTERIZE" // every pixel replaced by the nearest palette color
+	TRANSFER  Mode = "TRANSFER"  // colors of reference image (-k reference=) applied to input
+	PAINT_BY_NUMBER Mode = "PAINT-BY-NUMBER" // numbered outline sheet with legend, png + svg
+	CROSS_STITCH Mode = "CROSS-STITCH" // tile per stitch chart of floss colors with legend, png + csv
+	MOSAIC    Mode = "MOSAIC"    // tile per 1x1 plate of brick colors with studs, png + bill of materials csv
 )
 var Modes = map[Mode]string {
 	GRID: "GRID",
 	PALLETE: "PALLETE",
+	QUADTREE: "QUADTREE",
+	PIXELATE: "PIXELATE",
+	SPRITE: "SPRITE",
+	DITHER: "DITHER",
+	POSTERIZE: "POSTERIZE",
+	TRANSFER: "TRANSFER",
+	PAINT_BY_NUMBER: "PAINT-BY-NUMBER",
+	CROSS_STITCH: "CROSS-STITCH",
+	MOSAIC: "MOSAIC",
 }
 
+// rendered when -m is not set, other modes are opt-in
+var DEFAULT_MODES = []Mode{ GRID, PALLETE }
+
+type Verbosity string
+const (
+	QUIET   Verbosity = "quiet"
+	NORMAL  Verbosity = "normal"
+	VERBOSE Verbosity = "verbose"
+)
+
+var EXPORT_FORMATS = [...]string { "hex", "gpl", "json" }
+
 type Config struct {
+	Command Command
+
 	InputFiles []string
 
+	// input discovery filters for -f folders and glob patterns
+	Include  []string
+	Exclude  []string
+	MaxDepth int
+
 	GridRows int
 	GridCols int
 	gridSet  bool
 
+	// tile size in pixels, replaces rows / cols when set, e.g. -g 32x48px
+	TileWidth  int
+	TileHeight int
+
+	// square cells, replace rows / cols when set
+	SquareCount int    // cells along the shorter side
+	SquareSize  int    // cell size in pixels
+	SquareFit   string // center | crop leftover pixels
+
 	OutputWidth  int
 	OutputHeight int
 
 	Modes []string
+
+	GridStyle GridStyle
+
+	ModeOptions ModeOptions
+
+	Tessellation Tessellation
+
+	ExportFormats []string
+
+	Preset  string
+	Presets map[string]Preset // user-defined, from config file
+
+	OutputDir string // next to input files when empty
+	Workers   int
+
+	ReportPath string // json report of a run, .ndjson / .jsonl for one record per line
+
+	Verbosity Verbosity
+
+	LogFormat LogFormat
+	LogLevel  string
+
+	configFile string // applied config file, for diagnostics
 }
 
 func (c *Config) SetDefaults() {
+	c.applyPreset()
+
 	if !c.gridSet {
 		c.GridRows = DEFAULT_ROWS
 		c.GridCols = DEFAULT_COLS
 	}
 
+	if c.Command == "" {
+		c.Command = RENDER
+	}
+
 	if len(c.Modes) == 0 {
-		for _, v := range Modes {
-			c.Modes = append(c.Modes, v)
+		// resolution is a pallete option, don't render grids next to it
+		if c.hasOutputResolution() {
+			c.Modes = append(c.Modes, string(PALLETE))
+		} else {
+			for _, m := range DEFAULT_MODES {
+				c.Modes = append(c.Modes, string(m))
+			}
 		}
 	}
+
+	if c.GridStyle == (GridStyle{}) {
+		c.GridStyle = DEFAULT_GRID_STYLE
+	}
+	if c.ModeOptions == (ModeOptions{}) {
+		c.ModeOptions = DEFAULT_MODE_OPTIONS
+	}
+
+	if c.Workers == 0 {
+		c.Workers = runtime.NumCPU()
+	}
+
+	if c.Verbosity == "" {
+		c.Verbosity = NORMAL
+	}
+
+	if c.LogFormat == "" {
+		c.LogFormat = TEXT
+	}
+	if c.LogLevel == "" {
+		c.LogLevel = "warn"
+	}
+
+	if c.Command == EXPORT && len(c.ExportFormats) == 0 {
+		c.ExportFormats = []string{ "hex" }
+	}
 }
 
 func (c *Config) Validate() []error {
@@ -60,11 +171,17 @@ func (c *Config) Validate() []error {
 	}
 
 	// grid cols / rows
-	if c.GridRows < 1 {
-		errs = append(errs, errors.New("number of grid rows must be > 1. got " + strconv.Itoa(c.GridRows)))
-	}
-	if c.GridCols < 1 {
-		errs = append(errs, errors.New("number of grid columns must be > 1. got " + strconv.Itoa(c.GridCols)))
+	if c.IsSizedGrid() {
+		if c.TileWidth < 1 || c.TileHeight < 1 {
+			errs = append(errs, errors.New("tile size must be > 0. got " + strconv.Itoa(c.TileWidth) + "x" + strconv.Itoa(c.TileHeight)))
+		}
+	} else {
+		if c.GridRows < 1 {
+			errs = append(errs, errors.New("number of grid rows must be > 1. got " + strconv.Itoa(c.GridRows)))
+		}
+		if c.GridCols < 1 {
+			errs = append(errs, errors.New("number of grid columns must be > 1. got " + strconv.Itoa(c.GridCols)))
+		}
 	}
 
 	// modes
@@ -72,11 +189,126 @@ func (c *Config) Validate() []error {
 		if !isValidMode(m) {
 			errs = append(errs, errors.New("invalid mode: " + m))
 		}
+		if c.hasOutputResolution() && !supportsOutputResolution(Mode(strings.ToUpper(m))) {
+			errs = append(errs, errors.New("output resolution (-r) is only supported by PALLETE and QUADTREE modes, got mode: " + m))
+		}
+		if c.IsTessellated() && !supportsTessellation(Mode(strings.ToUpper(m))) {
+			errs = append(errs, errors.New("tessellation (-t) is only supported by GRID and PALLETE modes, got mode: " + m))
+		}
+	}
+
+	// stdin / stdout
+	stdinCount := 0
+	for _, f := range c.InputFiles {
+		if f == STDIO { stdinCount++ }
+	}
+	if stdinCount > 1 {
+		errs = append(errs, errors.New("stdin (-) can be used as input only once"))
+	}
+	if stdinCount > 0 && c.ModeOptions.Reference == STDIO {
+		errs = append(errs, errors.New("stdin (-) can't be both input and transfer reference (-k reference=-)"))
+	}
+	if c.ReportPath == STDIO && c.OutputDir == STDIO {
+		errs = append(errs, errors.New("report (-j -) and output (-o -) can't both be written to stdout"))
+	}
+	if c.OutputDir == STDIO && c.outputsCount() != 1 {
+		errs = append(errs, errors.New("writing to stdout (-o -) requires exactly one output, got " + strconv.Itoa(c.outputsCount()) + ". use single input and single mode / export format"))
+	}
+
+	// workers, 0 picks number of CPUs
+	if c.Workers < 0 {
+		errs = append(errs, errors.New("number of workers must be >= 0. got " + strconv.Itoa(c.Workers)))
+	}
+
+	// verbosity
+	switch c.Verbosity {
+	case "", QUIET, NORMAL, VERBOSE:
+	default:
+		errs = append(errs, errors.New("invalid verbosity: " + string(c.Verbosity) + ". available levels: quiet, normal, verbose"))
+	}
+
+	// logging
+	if c.LogFormat != "" && c.LogFormat != TEXT && c.LogFormat != JSON {
+		errs = append(errs, errors.New("invalid log format: " + string(c.LogFormat) + ". available formats: text, json"))
+	}
+	if _, ok := LOG_LEVELS[c.LogLevel]; c.LogLevel != "" && !ok {
+		errs = append(errs, errors.New("invalid log level: " + c.LogLevel + ". available levels: debug, info, warn, error"))
+	}
+
+	// preset
+	if c.Preset != "" {
+		if _, ok := c.lookupPreset(); !ok {
+			errs = append(errs, errors.New("unknown preset: " + c.Preset + ". available presets: " + strings.Join(c.presetNames(), ", ")))
+		}
+	}
+
+	// mode options
+	if c.ModeOptions.Palette != "" {
+		if _, err := os.Stat(c.ModeOptions.Palette); err != nil {
+			errs = append(errs, errors.New("palette file (-k palette=) can't be read: " + err.Error()))
+		}
+	}
+
+	if c.hasMode(TRANSFER) && c.ModeOptions.Reference == "" {
+		errs = append(errs, errors.New("TRANSFER mode requires reference image. syntax: -k reference=look.jpg"))
+	}
+	if c.ModeOptions.Reference != "" && c.ModeOptions.Reference != STDIO {
+		if _, err := os.Stat(c.ModeOptions.Reference); err != nil {
+			errs = append(errs, errors.New("reference image (-k reference=) can't be read: " + err.Error()))
+		}
+	}
+
+	// tessellation
+	if c.IsTessellated() && (c.GridStyle.Labels || c.GridStyle.CellLabels) {
+		errs = append(errs, errors.New("grid labels are only supported by rect tessellation, got: " + string(c.Tessellation)))
+	}
+
+	// export formats
+	for _, f := range c.ExportFormats {
+		if !isValidExportFormat(f) {
+			errs = append(errs, errors.New("invalid export format: " + f + ". available formats: hex, gpl, json"))
+		}
 	}
 
 	return errs
 }
 
+// outputsCount is number of files written by render or export
+func (c *Config) outputsCount() int {
+	if c.Command == EXPORT {
+		return len(c.InputFiles) * len(c.ExportFormats)
+	}
+	return len(c.InputFiles) * len(c.Modes)
+}
+
+func (c *Config) hasMode(mode Mode) bool {
+	for _, m := range c.Modes {
+		if strings.ToUpper(m) == string(mode) { return true }
+	}
+	return false
+}
+
+func (c *Config) hasOutputResolution() bool {
+	return c.OutputWidth > 0 && c.OutputHeight > 0
+}
+
+func isValidExportFormat(f string) bool {
+	for _, v := range EXPORT_FORMATS {
+		if f == v { return true }
+	}
+	return false
+}
+
+// other modes keep source size (GRID) or derive it from the grid (PIXELATE, SPRITE)
+func supportsOutputResolution(m Mode) bool {
+	return m == PALLETE || m == QUADTREE
+}
+
+// other modes paint rect tiles (QUADTREE splits them, charts count stitches, plates & regions on them)
+func supportsTessellation(m Mode) bool {
+	return m == GRID || m == PALLETE
+}
+
 func isValidMode(m string) bool {
 	for _, v := range Modes {
 		if strings.ToUpper(m) == v { return true }
@@ -84,24 +316,55 @@ func isValidMode(m string) bool {
 	return false
 }
 
-func (c *Config) addInputFiles(files []string) {
-	c.InputFiles = append(c.InputFiles, files...)
+func (c *Config) addInputFiles(files []string) error {
+	for _, f := range files {
+		if !hasGlobMeta(f) {
+			c.InputFiles = append(c.InputFiles, f)
+			continue
+		}
+		matches, err := c.expandGlob(f)
+		if err != nil { return err }
+		c.InputFiles = append(c.InputFiles, matches...)
+	}
+	return nil
+}
+
+// NormalizePath makes paths of the same file comparable, stdin is left as is
+func NormalizePath(path string) string {
+	if path == STDIO {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// dedupeInputFiles keeps the first of inputs naming the same file, e.g. -i a.png -f . from a.png folder,
+// otherwise both would write the same output at once
+func (c *Config) dedupeInputFiles() {
+	if len(c.InputFiles) < 2 {
+		return
+	}
+	seen := make(map[string]bool, len(c.InputFiles))
+	files := make([]string, 0, len(c.InputFiles))
+	for _, f := range c.InputFiles {
+		key := NormalizePath(f)
+		if seen[key] { continue }
+		seen[key] = true
+		files = append(files, f)
+	}
+	c.InputFiles = files
 }
 
 func (c *Config) addFolders(folders []string) error {
 	for _, f := range folders {
-		err := filepath.Walk(f, func(path string, info os.FileInfo, err error) error {
-			if err != nil { return err }
-			
-			if !info.IsDir() && isImageFile(path) {
-				c.InputFiles = append(c.InputFiles, path)
-			}
-
-			return nil
-		})
+		files, err := c.walkImages(f, c.MaxDepth, nil)
 		if err != nil {
 			return err
 		}
+		slog.Debug("folder scanned", "folder", f, "files", len(files), "maxDepth", c.MaxDepth)
+		c.InputFiles = append(c.InputFiles, files...)
 	}
 	return nil
 }
@@ -121,8 +384,132 @@ func (c *Config) setModes(args []string) {
 	}
 }
 
+const (
+	FIT_CENTER = "center"
+	FIT_CROP   = "crop"
+)
+
+func (c *Config) IsSquareGrid() bool {
+	return c.SquareCount > 0 || c.SquareSize > 0
+}
+
+// setSquareGrid accepts cells count or cell size, and optional fit, e.g. -q 12, -q 32px crop
+func (c *Config) setSquareGrid(args []string) error {
+	const syntax = "acceptable syntax: [12] [32px] [12 crop] [32px center]"
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("wrong arguments for square grid. " + syntax)
+	}
+
+	// last grid source wins, square cells replace -g rows / columns & tile size
+	c.SquareCount, c.SquareSize, c.SquareFit = 0, 0, FIT_CENTER
+	c.TileWidth, c.TileHeight = 0, 0
+	value := strings.ToLower(args[0])
+	if strings.HasSuffix(value, "px") {
+		size, err := strconv.Atoi(strings.TrimSuffix(value, "px"))
+		if err != nil || size < 1 { return errors.New("can't convert value: " + args[0] + " to square cell size. " + syntax) }
+		c.SquareSize = size
+	} else {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 { return errors.New("can't convert value: " + args[0] + " to number of square cells. " + syntax) }
+		c.SquareCount = count
+	}
+
+	if len(args) == 2 {
+		fit := strings.ToLower(args[1])
+		if fit != FIT_CENTER && fit != FIT_CROP {
+			return errors.New("unknown square grid fit: " + args[1] + ". " + syntax)
+		}
+		c.SquareFit = fit
+	}
+	return nil
+}
+
+func (c *Config) setExportFormats(args []string) {
+	c.ExportFormats = make([]string, len(args))
+	for i, a := range args {
+		c.ExportFormats[i] = strings.ToLower(a)
+	}
+}
+
+func (c *Config) setInclude(args []string) error {
+	if len(args) == 0 {
+		return errors.New("not enough arguments for include. syntax: -n \"*.jpg\" [\"photos/**\"]")
+	}
+	c.Include = append(c.Include, args...)
+	return nil
+}
+
+func (c *Config) setExclude(args []string) error {
+	if len(args) == 0 {
+		return errors.New("not enough arguments for exclude. syntax: -x \"*-grid.*\" [thumbs]")
+	}
+	c.Exclude = append(c.Exclude, args...)
+	return nil
+}
+
+func (c *Config) setMaxDepth(args []string) error {
+	if len(args) != 1 {
+		return errors.New("max depth flag expects exactly one number. syntax: -d 2")
+	}
+	depth, err := strconv.Atoi(args[0])
+	if err != nil || depth < 0 {
+		return errors.New("can't convert value: " + args[0] + " to max depth")
+	}
+	c.MaxDepth = depth
+	return nil
+}
+
+func (c *Config) setOutputDir(args []string) error {
+	if len(args) != 1 {
+		return errors.New("output flag expects exactly one folder. syntax: -o output/folder")
+	}
+	c.OutputDir = args[0]
+	return nil
+}
+
+func (c *Config) setReportPath(args []string) error {
+	if len(args) != 1 {
+		return errors.New("report flag expects exactly one file. syntax: -j report.json")
+	}
+	c.ReportPath = args[0]
+	return nil
+}
+
+func (c *Config) setVerbosity(args []string) error {
+	if len(args) != 1 {
+		return errors.New("verbosity flag expects exactly one level. syntax: -v quiet | normal | verbose")
+	}
+	c.Verbosity = Verbosity(strings.ToLower(args[0]))
+	return nil
+}
+
+func (c *Config) setWorkers(args []string) error {
+	if len(args) != 1 {
+		return errors.New("workers flag expects exactly one number. syntax: -w 4")
+	}
+	workers, err := strconv.Atoi(args[0])
+	if err != nil { return errors.New("can't convert value: " + args[0] + " to number of workers") }
+	c.Workers = workers
+	return nil
+}
+
+func (c *Config) IsSizedGrid() bool {
+	return c.TileWidth > 0 || c.TileHeight > 0
+}
+
 func (c *Config) setGrid(args []string) error {
-	const syntax = "acceptable syntax: [10x10] [10*10] [10 10]"
+	const syntax = "acceptable syntax: [10x10] [10*10] [10 10] [32px] [32x48px]"
+	if len(args) > 0 && strings.HasSuffix(strings.ToLower(args[len(args) - 1]), "px") {
+		if len(args) > 2 {
+			return errors.New("too many arguments for grid. " + syntax)
+		}
+		err := c.parseTileSizeFromString(strings.Join(args, " "))
+		if err != nil { return err }
+		c.clearSquareGrid()
+		c.gridSet = true
+		return nil
+	}
+
 	switch len(args) {
 	case 0:
 		return errors.New("not enough arguments for grid. " + syntax)
@@ -136,10 +523,16 @@ func (c *Config) setGrid(args []string) error {
 		return errors.New("too many arguments for grid. " + syntax)
 	}
 
+	c.clearSquareGrid()
 	c.gridSet = true
 	return nil
 }
 
+// clearSquareGrid drops square cells of an earlier source, last grid source wins
+func (c *Config) clearSquareGrid() {
+	c.SquareCount, c.SquareSize, c.SquareFit = 0, 0, ""
+}
+
 func (c *Config) parseGridFromString(str string) error {
 	rc, err := makeUniformPair(str)
 	if err != nil {
@@ -151,6 +544,26 @@ func (c *Config) parseGridFromString(str string) error {
 	cols, err := strconv.Atoi(rc[1])
 	if err != nil { return errors.New("can't convert value: " + rc[1] + " to number of columns") }
 	c.GridRows, c.GridCols = rows, cols
+	c.TileWidth, c.TileHeight = 0, 0
+	return nil
+}
+
+// parseTileSizeFromString accepts width x height in pixels, single value for square tiles
+func (c *Config) parseTileSizeFromString(str string) error {
+	str = strings.TrimSuffix(strings.ToLower(str), "px")
+	wh := []string{ str, str }
+	if strings.ContainsAny(str, "x* ") {
+		var err error
+		if wh, err = makeUniformPair(str); err != nil {
+			return errors.New("wrong tile size format, acceptable formats: [32px] [32x48px]")
+		}
+	}
+
+	width, err := strconv.Atoi(strings.TrimSuffix(wh[0], "px"))
+	if err != nil || width < 1 { return errors.New("can't convert value: " + wh[0] + " to tile width") }
+	height, err := strconv.Atoi(wh[1])
+	if err != nil || height < 1 { return errors.New("can't convert value: " + wh[1] + " to tile height") }
+	c.TileWidth, c.TileHeight = width, height
 	return nil
 }
 
@@ -308,3 +308,309 @@ func TestSetModes(t *testing.T) {
 	assert.Len(config.Modes, 1)
 	assert.Equal(config.Modes[0], "GRID")
 }
+
+func TestSetDefaults_ResolutionModes(t *testing.T) {
+	config := Config{ OutputWidth: 100, OutputHeight: 100 }
+
+	config.SetDefaults()
+
+	assert.Equal(t, []string{ "PALLETE" }, config.Modes)
+}
+
+func TestValidate_ResolutionWithGrid(t *testing.T) {
+	config := Config {
+		InputFiles: []string{ "input.jpg" },
+		GridRows: 8,
+		GridCols: 8,
+		OutputWidth: 100,
+		OutputHeight: 100,
+		Modes: []string{ "GRID" },
+	}
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "only supported by PALLETE")
+}
+
+func TestValidate_ExportFormats(t *testing.T) {
+	config := Config {
+		InputFiles: []string{ "input.jpg" },
+		GridRows: 8,
+		GridCols: 8,
+		ExportFormats: []string{ "gpl", "aco" },
+	}
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "invalid export format: aco")
+}
+
+// STDIO
+
+func TestValidate_StdoutSingleOutput(t *testing.T) {
+	config := Config {
+		InputFiles: []string{ "-" },
+		GridRows: 8,
+		GridCols: 8,
+		Modes: []string{ "GRID", "PALLETE" },
+		OutputDir: "-",
+	}
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "requires exactly one output")
+
+	config.Modes = []string{ "PALLETE" }
+	assert.Len(t, config.Validate(), 0)
+}
+
+func TestValidate_StdinOnce(t *testing.T) {
+	config := Config { InputFiles: []string{ "-", "-" }, GridRows: 8, GridCols: 8 }
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "only once")
+}
+
+// SQUARE
+
+func TestSetSquareGrid(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	err := config.setSquareGrid([]string{ "12" })
+	assert.Nil(err)
+	assert.Equal(12, config.SquareCount)
+	assert.Equal(FIT_CENTER, config.SquareFit)
+
+	err = config.setSquareGrid([]string{ "32px", "crop" })
+	assert.Nil(err)
+	assert.Equal(0, config.SquareCount)
+	assert.Equal(32, config.SquareSize)
+	assert.Equal(FIT_CROP, config.SquareFit)
+
+	assert.ErrorContains(config.setSquareGrid([]string{ "0px" }), "square cell size")
+	assert.ErrorContains(config.setSquareGrid([]string{ "12", "stretch" }), "unknown square grid fit")
+}
+
+// TILE SIZE
+
+func TestSetGrid_TileSize(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.Nil(config.setGrid([]string{ "32px" }))
+	assert.Equal(32, config.TileWidth)
+	assert.Equal(32, config.TileHeight)
+	assert.True(config.IsSizedGrid())
+
+	assert.Nil(config.setGrid([]string{ "32x48px" }))
+	assert.Equal(32, config.TileWidth)
+	assert.Equal(48, config.TileHeight)
+
+	assert.Nil(config.setGrid([]string{ "16", "24px" }))
+	assert.Equal(16, config.TileWidth)
+	assert.Equal(24, config.TileHeight)
+
+	// counts replace tile size
+	assert.Nil(config.setGrid([]string{ "4x4" }))
+	assert.False(config.IsSizedGrid())
+}
+
+func TestSetGrid_TileSizeInvalid(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.ErrorContains(config.setGrid([]string{ "0px" }), "tile width")
+	assert.ErrorContains(config.setGrid([]string{ "32xpx" }), "tile height")
+	assert.ErrorContains(config.setGrid([]string{ "axbpx" }), "tile width")
+	assert.ErrorContains(config.setGrid([]string{ "1", "2", "3px" }), "too many arguments")
+}
+
+func TestValidate_TileSizeSkipsRowsCols(t *testing.T) {
+	config := Config{ InputFiles: []string{ "filename.png" }, TileWidth: 32, TileHeight: 32 }
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 0)
+}
+
+// TESSELLATION
+
+func TestSetTessellation(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.Nil(config.setTessellation([]string{ "HEX" }))
+	assert.Equal(HEX, config.Tessellation)
+	assert.True(config.IsTessellated())
+
+	assert.Nil(config.setTessellation([]string{ "slic" }))
+	assert.Equal(SLIC, config.Tessellation)
+
+	assert.Nil(config.setTessellation([]string{ "rect" }))
+	assert.False(config.IsTessellated())
+
+	assert.ErrorContains(config.setTessellation([]string{ "circle" }), "unknown tessellation")
+	assert.ErrorContains(config.setTessellation([]string{}), "wrong arguments")
+}
+
+func TestValidate_TessellationLabels(t *testing.T) {
+	config := Config{
+		InputFiles: []string{ "filename.png" },
+		GridRows: 5,
+		GridCols: 5,
+		Tessellation: HEX,
+		GridStyle: GridStyle{ Labels: true },
+	}
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "rect tessellation")
+}
+
+// MODE OPTIONS
+
+func TestSetModeOptions(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.Nil(config.setModeOptions([]string{ "depth=8" }))
+	assert.Equal(8, config.ModeOptions.Depth)
+	assert.Equal(DEFAULT_MODE_OPTIONS.Threshold, config.ModeOptions.Threshold)
+
+	assert.Nil(config.setModeOptions([]string{ "threshold=12.5" }))
+	assert.Equal(12.5, config.ModeOptions.Threshold)
+
+	assert.ErrorContains(config.setModeOptions([]string{ "depth=-1" }), "wrong mode option value")
+	assert.Nil(config.setModeOptions([]string{ "scale=4" }))
+	assert.Equal(4, config.ModeOptions.Scale)
+	assert.ErrorContains(config.setModeOptions([]string{ "scale=0" }), "wrong mode option value")
+
+	assert.ErrorContains(config.setModeOptions([]string{ "speed=1" }), "unknown mode option")
+}
+
+func TestValidate_QuadtreeMode(t *testing.T) {
+	config := Config{ InputFiles: []string{ "filename.png" }, GridRows: 1, GridCols: 1, Modes: []string{ "QUADTREE" } }
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 0)
+}
+
+func TestValidate_TessellationUnsupportedModes(t *testing.T) {
+	modes := []string{ "QUADTREE", "PIXELATE", "SPRITE", "PAINT-BY-NUMBER", "CROSS-STITCH", "MOSAIC" }
+	config := Config{
+		InputFiles: []string{ "filename.png" },
+		GridRows: 5,
+		GridCols: 5,
+		Tessellation: HEX,
+		Modes: append([]string{ "GRID", "PALLETE" }, modes...),
+	}
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, len(modes))
+	for i, m := range modes {
+		assert.ErrorContains(t, errs[i], "got mode: " + m)
+	}
+}
+
+func TestValidate_ResolutionNotSupportedBySprite(t *testing.T) {
+	config := Config{
+		InputFiles: []string{ "filename.png" },
+		GridRows: 2,
+		GridCols: 2,
+		OutputWidth: 10,
+		OutputHeight: 10,
+		Modes: []string{ "PALLETE", "SPRITE" },
+	}
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "got mode: SPRITE")
+}
+
+func TestSetModeOptions_Dither(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.Nil(config.setModeOptions([]string{ "colors=16", "dither=Atkinson" }))
+	assert.Equal(16, config.ModeOptions.Colors)
+	assert.Equal(ATKINSON, config.ModeOptions.Dither)
+
+	assert.ErrorContains(config.setModeOptions([]string{ "colors=1" }), "2..256")
+	assert.ErrorContains(config.setModeOptions([]string{ "dither=random" }), "available methods")
+}
+
+func TestSetModeOptions_Palette(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.Nil(config.setModeOptions([]string{ "palette=Brand/Colors.GPL", "metric=OKLab" }))
+	assert.Equal("Brand/Colors.GPL", config.ModeOptions.Palette)
+	assert.Equal(OKLAB, config.ModeOptions.Metric)
+
+	assert.ErrorContains(config.setModeOptions([]string{ "metric=hsv" }), "available metrics")
+	assert.ErrorContains(config.setModeOptions([]string{ "palette=" }), "path is empty")
+}
+
+func TestValidate_MissingPalette(t *testing.T) {
+	config := Config{
+		InputFiles: []string{ "filename.png" },
+		GridRows: 2,
+		GridCols: 2,
+		Modes: []string{ "POSTERIZE" },
+		ModeOptions: ModeOptions{ Palette: "missing.gpl" },
+	}
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "palette file")
+}
+
+func TestValidate_TransferRequiresReference(t *testing.T) {
+	config := Config{ InputFiles: []string{ "filename.png" }, GridRows: 2, GridCols: 2, Modes: []string{ "TRANSFER" } }
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "requires reference image")
+}
+
+func TestValidate_ReferenceAndInputFromStdin(t *testing.T) {
+	config := Config{ InputFiles: []string{ "-" }, GridRows: 2, GridCols: 2, Modes: []string{ "TRANSFER" }, ModeOptions: ModeOptions{ Reference: "-" } }
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "transfer reference")
+}
+
+func TestSetModeOptions_Transfer(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.Nil(config.setModeOptions([]string{ "reference=Looks/Sunset.JPG", "transfer=palette" }))
+	assert.Equal("Looks/Sunset.JPG", config.ModeOptions.Reference)
+	assert.Equal(PALETTE_TRANSFER, config.ModeOptions.Transfer)
+
+	assert.ErrorContains(config.setModeOptions([]string{ "transfer=histogram" }), "available methods")
+}
+
+func TestSetModeOptions_MinRegion(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.Nil(config.setModeOptions([]string{ "min-region=50" }))
+	assert.Equal(50, config.ModeOptions.MinRegion)
+
+	assert.ErrorContains(config.setModeOptions([]string{ "min-region=-1" }), "wrong mode option value")
+}
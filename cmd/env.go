@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+)
+
+// every variable is PALLETE_ + name, e.g. PALLETE_GRID=6x6
+const ENV_PREFIX = "PALLETE_"
+
+// list values are separated by commas or spaces, e.g. PALLETE_MODES=grid,pallete
+func splitEnvList(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// applyEnv sets values from environment, called between config file and flags
+func (c *Config) applyEnv(lookupEnv func(string) (string, bool)) []error {
+	errs := make([]error, 0)
+	get := func(name string) (string, bool) {
+		v, ok := lookupEnv(ENV_PREFIX + name)
+		return strings.TrimSpace(v), ok && strings.TrimSpace(v) != ""
+	}
+	wrap := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, errors.New("env " + ENV_PREFIX + name + ": " + err.Error()))
+		}
+	}
+
+	if v, ok := get("GRID"); ok {
+		wrap("GRID", c.setGrid([]string{ v }))
+	}
+	if v, ok := get("SQUARE"); ok {
+		wrap("SQUARE", c.setSquareGrid(strings.Fields(v)))
+	}
+	if v, ok := get("RESOLUTION"); ok {
+		wrap("RESOLUTION", c.setOutputResolution([]string{ v }))
+	}
+	if v, ok := get("MODES"); ok {
+		c.setModes(splitEnvList(v))
+	}
+	if v, ok := get("GRID_STYLE"); ok {
+		wrap("GRID_STYLE", c.setGridStyle(strings.Fields(v)))
+	}
+	if v, ok := get("MODE_OPTIONS"); ok {
+		wrap("MODE_OPTIONS", c.setModeOptions(strings.Fields(v)))
+	}
+	if v, ok := get("TESSELLATION"); ok {
+		wrap("TESSELLATION", c.setTessellation([]string{ v }))
+	}
+	if v, ok := get("EXPORT_FORMATS"); ok {
+		c.setExportFormats(splitEnvList(v))
+	}
+	if v, ok := get("INCLUDE"); ok {
+		c.Include = nil
+		wrap("INCLUDE", c.setInclude(splitEnvList(v)))
+	}
+	if v, ok := get("EXCLUDE"); ok {
+		c.Exclude = nil
+		wrap("EXCLUDE", c.setExclude(splitEnvList(v)))
+	}
+	if v, ok := get("MAX_DEPTH"); ok {
+		wrap("MAX_DEPTH", c.setMaxDepth([]string{ v }))
+	}
+	if v, ok := get("OUTPUT_DIR"); ok {
+		wrap("OUTPUT_DIR", c.setOutputDir([]string{ v }))
+	}
+	if v, ok := get("REPORT"); ok {
+		wrap("REPORT", c.setReportPath([]string{ v }))
+	}
+	if v, ok := get("VERBOSITY"); ok {
+		wrap("VERBOSITY", c.setVerbosity([]string{ v }))
+	}
+	if v, ok := get("LOG_FORMAT"); ok {
+		c.LogFormat = LogFormat(strings.ToLower(v))
+	}
+	if v, ok := get("LOG_LEVEL"); ok {
+		c.LogLevel = strings.ToLower(v)
+	}
+	if v, ok := get("WORKERS"); ok {
+		wrap("WORKERS", c.setWorkers([]string{ v }))
+	}
+	if v, ok := get("PRESET"); ok {
+		wrap("PRESET", c.setPreset([]string{ v }))
+	}
+
+	return errs
+}
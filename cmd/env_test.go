@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func noEnv(string) (string, bool) {
+	return "", false
+}
+
+func fakeEnv(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestApplyEnv_AllFields(t *testing.T) {
+	assert := assert.New(t)
+	env := fakeEnv(map[string]string{
+		"PALLETE_GRID": "6x4",
+		"PALLETE_RESOLUTION": "300*200",
+		"PALLETE_MODES": "grid, pallete",
+		"PALLETE_OUTPUT_DIR": "out",
+		"PALLETE_WORKERS": "3",
+		"PALLETE_PRESET": "Banner",
+	})
+	config := Config{}
+
+	errs := config.applyEnv(env)
+
+	assert.Len(errs, 0)
+	assert.Equal(6, config.GridRows)
+	assert.Equal(4, config.GridCols)
+	assert.Equal(300, config.OutputWidth)
+	assert.Equal(200, config.OutputHeight)
+	assert.Equal([]string{ "GRID", "PALLETE" }, config.Modes)
+	assert.Equal("out", config.OutputDir)
+	assert.Equal(3, config.Workers)
+	assert.Equal("banner", config.Preset)
+}
+
+func TestApplyEnv_InvalidValue(t *testing.T) {
+	config := Config{}
+
+	errs := config.applyEnv(fakeEnv(map[string]string{ "PALLETE_WORKERS": "many" }))
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "PALLETE_WORKERS")
+}
+
+func TestApplyEnv_Precedence(t *testing.T) {
+	assert := assert.New(t)
+	path := writeConfigFile(t, `{ "grid": "2x2", "workers": 1, "outputDir": "file-out" }`)
+	env := fakeEnv(map[string]string{ "PALLETE_CONFIG": path, "PALLETE_GRID": "3x3", "PALLETE_WORKERS": "2" })
+	args := []string{ "-i", "input.jpg", "-w", "5" }
+	flagsPos := FindAllFlags(args)
+	config := Config{}
+
+	errs := config.loadConfigFile(args, flagsPos, env)
+	errs = append(errs, config.applyEnv(env)...)
+	errs = append(errs, config.applyFlags(args, flagsPos)...)
+
+	assert.Len(errs, 0)
+	assert.Equal("file-out", config.OutputDir)
+	assert.Equal(3, config.GridRows)
+	assert.Equal(5, config.Workers)
+}
+
+func TestApplyEnv_PatternsPrecedence(t *testing.T) {
+	assert := assert.New(t)
+	path := writeConfigFile(t, `{ "include": ["*.png"], "exclude": ["*-grid.*", "thumbs"] }`)
+	env := fakeEnv(map[string]string{ "PALLETE_CONFIG": path, "PALLETE_INCLUDE": "*.jpg" })
+	args := []string{ "-x", "*-old.*", "-i", "input.jpg", "-x", "tmp" }
+	flagsPos := FindAllFlags(args)
+	config := Config{}
+
+	errs := config.loadConfigFile(args, flagsPos, env)
+	errs = append(errs, config.applyEnv(env)...)
+	errs = append(errs, config.applyFlags(args, flagsPos)...)
+
+	assert.Len(errs, 0)
+	assert.Equal([]string{ "*.jpg" }, config.Include)
+	assert.Equal([]string{ "*-old.*", "tmp" }, config.Exclude)
+}
+
+func TestApplyEnv_GridPrecedence(t *testing.T) {
+	assert := assert.New(t)
+	path := writeConfigFile(t, `{ "grid": "32px", "square": "12" }`)
+	env := fakeEnv(map[string]string{ "PALLETE_CONFIG": path, "PALLETE_SQUARE": "20px crop" })
+	args := []string{ "-i", "input.jpg", "-g", "4x4" }
+	flagsPos := FindAllFlags(args)
+	config := Config{}
+
+	errs := config.loadConfigFile(args, flagsPos, env)
+	errs = append(errs, config.applyEnv(env)...)
+	assert.Equal(20, config.SquareSize)
+	assert.Equal(0, config.SquareCount)
+	assert.False(config.IsSizedGrid())
+	errs = append(errs, config.applyFlags(args, flagsPos)...)
+
+	assert.Len(errs, 0)
+	assert.False(config.IsSquareGrid())
+	assert.False(config.IsSizedGrid())
+	assert.Equal(4, config.GridRows)
+	assert.Equal(4, config.GridCols)
+}
+
+func TestApplyEnv_SquareOverridesFileGrid(t *testing.T) {
+	assert := assert.New(t)
+	path := writeConfigFile(t, `{ "grid": "32x48px" }`)
+	env := fakeEnv(map[string]string{ "PALLETE_CONFIG": path, "PALLETE_SQUARE": "12" })
+	args := []string{ "-i", "input.jpg" }
+	flagsPos := FindAllFlags(args)
+	config := Config{}
+
+	errs := config.loadConfigFile(args, flagsPos, env)
+	errs = append(errs, config.applyEnv(env)...)
+
+	assert.Len(errs, 0)
+	assert.Equal(12, config.SquareCount)
+	assert.False(config.IsSizedGrid())
+}
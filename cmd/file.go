@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// auto-discovered in the working directory when -c is not set, first found wins
+var CONFIG_FILENAMES = []string{ "pallete.json", "pallete.yaml", "pallete.yml", "pallete.toml" }
+
+type FileConfig struct {
+	Grid          string   `json:"grid"`
+	Square        string   `json:"square"` // same as -q, e.g. "32px crop"
+	Resolution    string   `json:"resolution"`
+	Modes         []string `json:"modes"`
+	GridStyle     string   `json:"gridStyle"` // same as -s, e.g. "color=auto width=2"
+	Tessellation  string   `json:"tessellation"`
+	ModeOptions   string   `json:"modeOptions"` // same as -k, e.g. "depth=6 threshold=20"
+	ExportFormats []string `json:"exportFormats"`
+
+	Include  []string `json:"include"`
+	Exclude  []string `json:"exclude"`
+	MaxDepth int      `json:"maxDepth"`
+
+	OutputDir string `json:"outputDir"`
+	Workers   int    `json:"workers"`
+	Report    string `json:"report"`
+	Verbosity string `json:"verbosity"`
+	LogFormat string `json:"logFormat"`
+	LogLevel  string `json:"logLevel"`
+
+	Preset  string                `json:"preset"`
+	Presets map[string]FilePreset `json:"presets"`
+}
+
+// LoadFileConfig picks format by extension: .yaml / .yml, .toml, anything else is json.
+// yaml & toml are converted to json first, so every format shares keys and unknown key checks
+func LoadFileConfig(path string) (FileConfig, error) {
+	var fc FileConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fc, err
+	}
+	fail := func(err error) (FileConfig, error) {
+		return fc, errors.New("can't parse config file " + path + ": " + err.Error())
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var values map[string]any
+		if err = yaml.Unmarshal(data, &values); err != nil {
+			return fail(err)
+		}
+		if data, err = json.Marshal(values); err != nil {
+			return fail(err)
+		}
+	case ".toml":
+		var values map[string]any
+		if err = toml.Unmarshal(data, &values); err != nil {
+			return fail(err)
+		}
+		if data, err = json.Marshal(values); err != nil {
+			return fail(err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&fc); err != nil {
+		return fail(err)
+	}
+	return fc, nil
+}
+
+// loadConfigFile applies file given by -c or PALLETE_CONFIG,
+// or pallete.json / .yaml / .yml / .toml from working directory if present
+func (c *Config) loadConfigFile(args []string, flagsPos map[int]string, lookupEnv func(string) (string, bool)) []error {
+	path := CONFIG_FILENAMES[0]
+	for _, name := range CONFIG_FILENAMES {
+		if _, err := os.Stat(name); err == nil {
+			path = name
+			break
+		}
+	}
+	explicit := false
+	if v, ok := lookupEnv(ENV_PREFIX + "CONFIG"); ok && v != "" {
+		path, explicit = v, true
+	}
+	flags, values := flagArgs(args, flagsPos)
+	for i, flag := range flags {
+		if flag != "-c" { continue }
+		if len(values[i]) != 1 {
+			return []error{ errors.New("config flag expects exactly one file. syntax: -c pallete.json") }
+		}
+		path, explicit = values[i][0], true
+	}
+
+	if _, err := os.Stat(path); err != nil && !explicit {
+		return nil
+	}
+
+	fc, err := LoadFileConfig(path)
+	if err != nil {
+		return []error{ err }
+	}
+	c.configFile = path
+	return c.applyFileConfig(fc)
+}
+
+func (c *Config) applyFileConfig(fc FileConfig) []error {
+	errs := make([]error, 0)
+
+	if fc.Grid != "" {
+		if err := c.setGrid([]string{ fc.Grid }); err != nil {
+			errs = append(errs, errors.New("config file: " + err.Error()))
+		}
+	}
+	if fc.Square != "" {
+		if err := c.setSquareGrid(strings.Fields(fc.Square)); err != nil {
+			errs = append(errs, errors.New("config file: " + err.Error()))
+		}
+	}
+	if fc.Resolution != "" {
+		if err := c.setOutputResolution([]string{ fc.Resolution }); err != nil {
+			errs = append(errs, errors.New("config file: " + err.Error()))
+		}
+	}
+	if len(fc.Modes) > 0 {
+		c.setModes(fc.Modes)
+	}
+	if fc.GridStyle != "" {
+		if err := c.setGridStyle(strings.Fields(fc.GridStyle)); err != nil {
+			errs = append(errs, errors.New("config file: " + err.Error()))
+		}
+	}
+	if fc.ModeOptions != "" {
+		if err := c.setModeOptions(strings.Fields(fc.ModeOptions)); err != nil {
+			errs = append(errs, errors.New("config file: " + err.Error()))
+		}
+	}
+	if fc.Tessellation != "" {
+		if err := c.setTessellation([]string{ fc.Tessellation }); err != nil {
+			errs = append(errs, errors.New("config file: " + err.Error()))
+		}
+	}
+	if len(fc.ExportFormats) > 0 {
+		c.setExportFormats(fc.ExportFormats)
+	}
+	if len(fc.Include) > 0 {
+		c.Include = fc.Include
+	}
+	if len(fc.Exclude) > 0 {
+		c.Exclude = fc.Exclude
+	}
+	if fc.MaxDepth > 0 {
+		c.MaxDepth = fc.MaxDepth
+	}
+	if fc.OutputDir != "" {
+		c.OutputDir = fc.OutputDir
+	}
+	if fc.Verbosity != "" {
+		c.Verbosity = Verbosity(strings.ToLower(fc.Verbosity))
+	}
+	if fc.LogFormat != "" {
+		c.LogFormat = LogFormat(strings.ToLower(fc.LogFormat))
+	}
+	if fc.LogLevel != "" {
+		c.LogLevel = strings.ToLower(fc.LogLevel)
+	}
+	if fc.Report != "" {
+		c.ReportPath = fc.Report
+	}
+	if fc.Workers != 0 {
+		c.Workers = fc.Workers
+	}
+	if fc.Preset != "" {
+		c.Preset = strings.ToLower(fc.Preset)
+	}
+	for name, fp := range fc.Presets {
+		p, err := fp.toPreset()
+		if err != nil {
+			errs = append(errs, errors.New("config file: preset " + name + ": " + err.Error()))
+			continue
+		}
+		if c.Presets == nil {
+			c.Presets = make(map[string]Preset)
+		}
+		c.Presets[strings.ToLower(name)] = p
+	}
+
+	return errs
+}
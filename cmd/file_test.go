@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	return writeNamedConfigFile(t, "pallete.json", content)
+}
+
+func writeNamedConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileConfig_Valid(t *testing.T) {
+	assert := assert.New(t)
+	path := writeConfigFile(t, `{ "grid": "6x6", "resolution": "1080x1080", "modes": ["pallete"] }`)
+
+	fc, err := LoadFileConfig(path)
+
+	assert.Nil(err)
+	assert.Equal("6x6", fc.Grid)
+	assert.Equal("1080x1080", fc.Resolution)
+	assert.Equal([]string{ "pallete" }, fc.Modes)
+}
+
+func TestLoadFileConfig_UnknownField(t *testing.T) {
+	path := writeConfigFile(t, `{ "gird": "6x6" }`)
+
+	_, err := LoadFileConfig(path)
+
+	assert.ErrorContains(t, err, "can't parse config file")
+}
+
+func TestLoadFileConfig_YAML(t *testing.T) {
+	assert := assert.New(t)
+	path := writeNamedConfigFile(t, "pallete.yaml", `
+grid: 6x6
+modes: [pallete, grid]
+gridStyle: color=#ffffff width=2 # comment
+workers: 4
+presets:
+  thumb:
+    grid: 4x4
+    resolution: 256x256
+`)
+
+	fc, err := LoadFileConfig(path)
+
+	assert.Nil(err)
+	assert.Equal("6x6", fc.Grid)
+	assert.Equal([]string{ "pallete", "grid" }, fc.Modes)
+	assert.Equal("color=#ffffff width=2", fc.GridStyle)
+	assert.Equal(4, fc.Workers)
+	assert.Equal("256x256", fc.Presets["thumb"].Resolution)
+}
+
+func TestLoadFileConfig_TOML(t *testing.T) {
+	assert := assert.New(t)
+	path := writeNamedConfigFile(t, "pallete.toml", `
+grid = "6x6" # comment
+gridStyle = "color=#ffffff width=2"
+exclude = [
+  "*-grid.*",
+  'thumbs',
+]
+workers = 4
+
+[presets.thumb]
+grid = "4x4"
+modes = ["pallete"]
+
+[presets]
+banner = { grid = "1x8", resolution = "1500x500" }
+`)
+
+	fc, err := LoadFileConfig(path)
+
+	assert.Nil(err)
+	assert.Equal("6x6", fc.Grid)
+	assert.Equal("color=#ffffff width=2", fc.GridStyle)
+	assert.Equal([]string{ "*-grid.*", "thumbs" }, fc.Exclude)
+	assert.Equal(4, fc.Workers)
+	assert.Equal([]string{ "pallete" }, fc.Presets["thumb"].Modes)
+	assert.Equal("1500x500", fc.Presets["banner"].Resolution)
+}
+
+func TestLoadFileConfig_InvalidTOML(t *testing.T) {
+	for _, data := range []string{ "maxDepth = 010", "grid = \"6x6\"\ngrid = \"4x4\"", "exclude = [\"a\"" } {
+		_, err := LoadFileConfig(writeNamedConfigFile(t, "pallete.toml", data))
+
+		assert.ErrorContains(t, err, "can't parse config file", data)
+	}
+}
+
+func TestLoadFileConfig_UnknownFieldYAMLAndTOML(t *testing.T) {
+	_, err := LoadFileConfig(writeNamedConfigFile(t, "pallete.yml", "gird: 6x6\n"))
+	assert.ErrorContains(t, err, "unknown field")
+
+	_, err = LoadFileConfig(writeNamedConfigFile(t, "pallete.toml", "gird = \"6x6\"\n"))
+	assert.ErrorContains(t, err, "unknown field")
+}
+
+func TestLoadConfigFile_FlagsOverride(t *testing.T) {
+	assert := assert.New(t)
+	path := writeConfigFile(t, `{ "grid": "6x6", "resolution": "1080x1080", "modes": ["pallete"] }`)
+	args := []string{ "-c", path, "-i", "input.jpg", "-g", "3x4" }
+	flagsPos := FindAllFlags(args)
+	config := Config{}
+
+	errs := config.loadConfigFile(args, flagsPos, noEnv)
+	errs = append(errs, config.applyFlags(args, flagsPos)...)
+
+	assert.Len(errs, 0)
+	assert.Equal(3, config.GridRows)
+	assert.Equal(4, config.GridCols)
+	assert.Equal(1080, config.OutputWidth)
+	assert.Equal([]string{ "PALLETE" }, config.Modes)
+}
+
+func TestLoadConfigFile_MissingExplicit(t *testing.T) {
+	args := []string{ "-c", filepath.Join(t.TempDir(), "missing.json") }
+	config := Config{}
+
+	errs := config.loadConfigFile(args, FindAllFlags(args), noEnv)
+
+	assert.Len(t, errs, 1)
+}
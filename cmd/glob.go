@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// MatchGlob matches path against pattern segment by segment,
+// "**" stands for any number of folders (including none)
+func MatchGlob(pattern, path string) bool {
+	patternParts := strings.Split(filepath.ToSlash(pattern), "/")
+	pathParts := strings.Split(filepath.ToSlash(path), "/")
+	return matchSegments(patternParts, pathParts)
+}
+
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(pattern[1:], path[i:]) { return true }
+			}
+			return false
+		}
+		if len(path) == 0 { return false }
+		ok, err := filepath.Match(pattern[0], path[0])
+		if err != nil || !ok { return false }
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// globBase is the longest folder prefix without wildcards, "photos/**/*.jpg" -> "photos"
+func globBase(pattern string) string {
+	parts := strings.Split(filepath.ToSlash(pattern), "/")
+	base := make([]string, 0, len(parts))
+	for _, p := range parts[:len(parts) - 1] {
+		if hasGlobMeta(p) { break }
+		base = append(base, p)
+	}
+	if len(base) == 0 {
+		return "."
+	}
+	if len(base) == 1 && base[0] == "" {
+		return "/"
+	}
+	return filepath.FromSlash(strings.Join(base, "/"))
+}
+
+// matchFilter matches patterns without "/" against file name,
+// others against path relative to the searched folder
+func matchFilter(pattern, rel string) bool {
+	if !strings.Contains(filepath.ToSlash(pattern), "/") {
+		return MatchGlob(pattern, filepath.Base(rel))
+	}
+	return MatchGlob(pattern, rel)
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchFilter(p, rel) { return true }
+	}
+	return false
+}
+
+func isHidden(name string) bool {
+	return len(name) > 1 && strings.HasPrefix(name, ".") && name != ".."
+}
+
+// walkImages collects image files under root, skipping hidden, excluded and previously generated entries.
+// maxDepth limits folder nesting, 1 - only files directly inside root, 0 - no limit
+func (c *Config) walkImages(root string, maxDepth int, match func(path string) bool) ([]string, error) {
+	files := make([]string, 0)
+	manifests := make(manifestCache)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil { return err }
+		rel, err := filepath.Rel(root, path)
+		if err != nil { return err }
+		if path == root {
+			if info.IsDir() { return nil }
+			// a file given instead of a folder is taken when it passes the filters, matched by its name
+			rel = info.Name()
+		}
+		depth := strings.Count(filepath.ToSlash(rel), "/") + 1
+
+		if info.IsDir() {
+			if isHidden(info.Name()) || matchAny(c.Exclude, rel) || (maxDepth > 0 && depth >= maxDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if (path != root && isHidden(info.Name())) || !isImageFile(path) || matchAny(c.Exclude, rel) {
+			return nil
+		}
+		if manifests.isOutput(path) {
+			return nil
+		}
+		if len(c.Include) > 0 && !matchAny(c.Include, rel) {
+			return nil
+		}
+		if match != nil && !match(path) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files, err
+}
+
+func (c *Config) expandGlob(pattern string) ([]string, error) {
+	pattern = filepath.Clean(pattern)
+	files, err := c.walkImages(globBase(pattern), 0, func(path string) bool {
+		return MatchGlob(pattern, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no image files match pattern: " + pattern)
+	}
+	slog.Debug("glob expanded", "pattern", pattern, "files", len(files))
+	return files, nil
+}
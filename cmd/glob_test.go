@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makeTree creates empty files under a temp folder and returns its path
+func makeTree(t *testing.T, files ...string) string {
+	root := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+var testTree = []string{
+	"a.jpg",
+	"a-grid.jpg",
+	"notes.txt",
+	".hidden.png",
+	"sub/b.png",
+	"sub/thumbs/b-small.png",
+	"sub/deep/c.webp",
+	".cache/d.jpg",
+}
+
+func TestMatchGlob(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(MatchGlob("*.jpg", "a.jpg"))
+	assert.False(MatchGlob("*.jpg", "sub/a.jpg"))
+	assert.True(MatchGlob("**/*.jpg", "a.jpg"))
+	assert.True(MatchGlob("**/*.jpg", "sub/deep/a.jpg"))
+	assert.True(MatchGlob("photos/**", "photos/sub/a.jpg"))
+	assert.False(MatchGlob("photos/**/*.png", "other/a.png"))
+}
+
+func TestGlobBase(t *testing.T) {
+	assert.Equal(t, ".", globBase("**/*.jpg"))
+	assert.Equal(t, filepath.FromSlash("photos/2024"), globBase("photos/2024/*/a?.jpg"))
+	assert.Equal(t, "/", globBase("/*.jpg"))
+}
+
+func TestAddFolders_SkipsHidden(t *testing.T) {
+	root := makeTree(t, testTree...)
+	config := Config{}
+
+	err := config.addFolders([]string{ root })
+
+	assert.Nil(t, err)
+	assert.Len(t, config.InputFiles, 5)
+}
+
+func TestAddFolders_MaxDepth(t *testing.T) {
+	root := makeTree(t, testTree...)
+	config := Config{ MaxDepth: 1 }
+
+	config.addFolders([]string{ root })
+
+	assert.ElementsMatch(t, []string{ filepath.Join(root, "a.jpg"), filepath.Join(root, "a-grid.jpg") }, config.InputFiles)
+}
+
+func TestAddFolders_IncludeExclude(t *testing.T) {
+	root := makeTree(t, testTree...)
+	config := Config{ Include: []string{ "*.png", "*.jpg" }, Exclude: []string{ "*-grid.*", "thumbs" } }
+
+	config.addFolders([]string{ root })
+
+	assert.ElementsMatch(t, []string{ filepath.Join(root, "a.jpg"), filepath.Join(root, "sub", "b.png") }, config.InputFiles)
+}
+
+func TestAddFolders_FileRoot(t *testing.T) {
+	root := makeTree(t, testTree...)
+	config := Config{ Exclude: []string{ "*-grid.*" } }
+
+	err := config.addFolders([]string{ filepath.Join(root, "a.jpg"), filepath.Join(root, "a-grid.jpg"), filepath.Join(root, "notes.txt") })
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{ filepath.Join(root, "a.jpg") }, config.InputFiles)
+}
+
+func TestAddInputFiles_Glob(t *testing.T) {
+	assert := assert.New(t)
+	root := makeTree(t, testTree...)
+	config := Config{}
+
+	err := config.addInputFiles([]string{ filepath.Join(root, "sub", "**", "*.png"), "plain.jpg" })
+
+	assert.Nil(err)
+	assert.ElementsMatch([]string{
+		filepath.Join(root, "sub", "b.png"),
+		filepath.Join(root, "sub", "thumbs", "b-small.png"),
+		"plain.jpg",
+	}, config.InputFiles)
+
+	err = config.addInputFiles([]string{ filepath.Join(root, "*.gif") })
+
+	assert.ErrorContains(err, "no image files match")
+}
+
+func TestApplyFlags_FiltersBeforeFolders(t *testing.T) {
+	root := makeTree(t, testTree...)
+	args := []string{ "-f", root, "-x", "*-grid.*", "-d", "2" }
+	config := Config{}
+
+	errs := config.applyFlags(args, FindAllFlags(args))
+
+	assert.Len(t, errs, 0)
+	assert.ElementsMatch(t, []string{ filepath.Join(root, "a.jpg"), filepath.Join(root, "sub", "b.png") }, config.InputFiles)
+}
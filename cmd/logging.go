@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type LogFormat string
+const (
+	TEXT LogFormat = "text"
+	JSON LogFormat = "json"
+)
+
+var LOG_LEVELS = map[string]slog.Level {
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// setLogging accepts format and / or level in any order, e.g. -l json debug
+func (c *Config) setLogging(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("wrong arguments for logging. syntax: -l [text | json] [debug | info | warn | error]")
+	}
+	for _, a := range args {
+		a = strings.ToLower(a)
+		if _, ok := LOG_LEVELS[a]; ok {
+			c.LogLevel = a
+			continue
+		}
+		if LogFormat(a) == TEXT || LogFormat(a) == JSON {
+			c.LogFormat = LogFormat(a)
+			continue
+		}
+		return errors.New("unknown logging option: " + a + ". formats: text, json. levels: debug, info, warn, error")
+	}
+	return nil
+}
+
+func NewLogger(w io.Writer, c Config) *slog.Logger {
+	level, ok := LOG_LEVELS[c.LogLevel]
+	if !ok {
+		level = slog.LevelWarn
+	}
+	opts := &slog.HandlerOptions{ Level: level }
+
+	if c.LogFormat == JSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// SetupLogging routes slog default logger to stderr
+func SetupLogging(c Config) {
+	slog.SetDefault(NewLogger(os.Stderr, c))
+}
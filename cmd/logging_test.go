@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogging(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	err := config.setLogging([]string{ "DEBUG", "json" })
+
+	assert.Nil(err)
+	assert.Equal(JSON, config.LogFormat)
+	assert.Equal("debug", config.LogLevel)
+
+	err = config.setLogging([]string{ "xml" })
+
+	assert.ErrorContains(err, "unknown logging option: xml")
+}
+
+func TestNewLogger_JSONLevel(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, Config{ LogFormat: JSON, LogLevel: "info" })
+
+	logger.Debug("hidden")
+	logger.Info("shown", "path", "a.jpg")
+
+	var line map[string]any
+	assert.Nil(json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal("shown", line["msg"])
+	assert.Equal("a.jpg", line["path"])
+}
+
+func TestApplyFlags_LoggingIsEarly(t *testing.T) {
+	args := []string{ "-i", "a.jpg", "-l", "json" }
+	config := Config{}
+
+	errs := config.applyEarlyFlags(args, FindAllFlags(args))
+
+	assert.Len(t, errs, 0)
+	assert.Equal(t, JSON, config.LogFormat)
+	assert.Len(t, config.InputFiles, 0)
+}
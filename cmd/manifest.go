@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// sidecar file listing images generated into its folder,
+// discovery skips them so repeated runs don't process own outputs
+const MANIFEST_FILENAME = ".pallete-manifest.json"
+
+type Manifest struct {
+	Outputs []string `json:"outputs"` // file names relative to manifest folder
+}
+
+// LoadManifest returns set of generated file names in dir, empty when there is no manifest
+func LoadManifest(dir string) map[string]bool {
+	outputs := make(map[string]bool)
+	data, err := os.ReadFile(filepath.Join(dir, MANIFEST_FILENAME))
+	if err != nil {
+		return outputs
+	}
+
+	var m Manifest
+	if err = json.Unmarshal(data, &m); err != nil {
+		return outputs
+	}
+	for _, name := range m.Outputs {
+		outputs[name] = true
+	}
+	return outputs
+}
+
+// RecordOutputs adds generated files to manifests of their folders
+func RecordOutputs(paths []string) error {
+	byDir := make(map[string][]string)
+	for _, p := range paths {
+		dir := filepath.Dir(p)
+		byDir[dir] = append(byDir[dir], filepath.Base(p))
+	}
+
+	for dir, names := range byDir {
+		outputs := LoadManifest(dir)
+		for _, name := range names {
+			outputs[name] = true
+		}
+
+		m := Manifest{ Outputs: make([]string, 0, len(outputs)) }
+		for name := range outputs {
+			m.Outputs = append(m.Outputs, name)
+		}
+		sort.Strings(m.Outputs)
+
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(dir, MANIFEST_FILENAME), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestCache loads every folder's manifest once per walk
+type manifestCache map[string]map[string]bool
+
+func (mc manifestCache) isOutput(path string) bool {
+	dir := filepath.Dir(path)
+	outputs, ok := mc[dir]
+	if !ok {
+		outputs = LoadManifest(dir)
+		mc[dir] = outputs
+	}
+	return outputs[filepath.Base(path)]
+}
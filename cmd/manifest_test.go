@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordOutputs_Merge(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	err := RecordOutputs([]string{ filepath.Join(dir, "a-grid.jpg") })
+	assert.Nil(err)
+	err = RecordOutputs([]string{ filepath.Join(dir, "a-pallete.jpg"), filepath.Join(dir, "a-grid.jpg") })
+	assert.Nil(err)
+
+	outputs := LoadManifest(dir)
+
+	assert.Equal(map[string]bool{ "a-grid.jpg": true, "a-pallete.jpg": true }, outputs)
+}
+
+func TestLoadManifest_Missing(t *testing.T) {
+	assert.Len(t, LoadManifest(t.TempDir()), 0)
+}
+
+func TestAddFolders_SkipsRecordedOutputs(t *testing.T) {
+	root := makeTree(t, "a.jpg", "a-grid.jpg", "sub/b.png", "sub/b-pallete.png")
+	RecordOutputs([]string{ filepath.Join(root, "a-grid.jpg"), filepath.Join(root, "sub", "b-pallete.png") })
+	config := Config{}
+
+	err := config.addFolders([]string{ root })
+
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{ filepath.Join(root, "a.jpg"), filepath.Join(root, "sub", "b.png") }, config.InputFiles)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type DitherMethod string
+const (
+	FLOYD_STEINBERG DitherMethod = "floyd-steinberg"
+	ATKINSON        DitherMethod = "atkinson"
+	BAYER           DitherMethod = "bayer" // ordered 8x8 threshold map
+	NO_DITHER       DitherMethod = "none"  // nearest palette color only
+)
+
+var DITHER_METHODS = []DitherMethod{ FLOYD_STEINBERG, ATKINSON, BAYER, NO_DITHER }
+
+// how distance between two colors is measured when picking the nearest palette color
+type ColorMetric string
+const (
+	RGB_METRIC ColorMetric = "rgb"
+	CIEDE2000  ColorMetric = "ciede2000" // perceptual, slowest
+	OKLAB      ColorMetric = "oklab"     // perceptual, cheap
+)
+
+var COLOR_METRICS = []ColorMetric{ RGB_METRIC, CIEDE2000, OKLAB }
+
+type TransferMethod string
+const (
+	REINHARD         TransferMethod = "reinhard" // match mean / deviation of Lab channels
+	PALETTE_TRANSFER TransferMethod = "palette"  // map extracted palette onto reference palette
+)
+
+var TRANSFER_METHODS = []TransferMethod{ REINHARD, PALETTE_TRANSFER }
+
+// settings of modes beyond GRID / PALLETE, set with -k key=value
+type ModeOptions struct {
+	Depth     int     // QUADTREE: how many times a grid tile can be split
+	Threshold float64 // QUADTREE: tile color deviation (0..255) above which it's split
+	Scale     int     // PIXELATE: integer upscale of source size
+
+	Colors  int          // DITHER, POSTERIZE, TRANSFER, PAINT-BY-NUMBER, CROSS-STITCH: palette size extracted from the image
+	Palette string       // DITHER, POSTERIZE, PAINT-BY-NUMBER: palette file (hex, .gpl, .json) used instead of extracted colors
+	Dither  DitherMethod // DITHER: how source colors are spread over the palette
+	Metric  ColorMetric  // POSTERIZE, PAINT-BY-NUMBER, CROSS-STITCH, MOSAIC: distance used to find the nearest palette color
+
+	MinRegion int // PAINT-BY-NUMBER: smaller regions (in pixels) are merged into neighbours, 0 - auto
+
+	Reference string         // TRANSFER: image whose colors are applied to inputs
+	Transfer  TransferMethod // TRANSFER: how colors are matched
+}
+
+var DEFAULT_MODE_OPTIONS = ModeOptions{ Depth: 6, Threshold: 20, Scale: 1, Colors: 8, Dither: FLOYD_STEINBERG, Metric: RGB_METRIC, Transfer: REINHARD }
+
+// setModeOptions updates options from key=value pairs, e.g. -k depth=8 threshold=12
+func (c *Config) setModeOptions(args []string) error {
+	const syntax = "syntax: -k depth=6 threshold=20 scale=1 colors=8 palette=brand.gpl dither=floyd-steinberg|atkinson|bayer|none metric=rgb|ciede2000|oklab reference=look.jpg transfer=reinhard|palette min-region=0"
+	if len(args) == 0 {
+		return errors.New("not enough arguments for mode options. " + syntax)
+	}
+	if c.ModeOptions == (ModeOptions{}) {
+		c.ModeOptions = DEFAULT_MODE_OPTIONS
+	}
+
+	for _, arg := range args {
+		key, raw, _ := strings.Cut(arg, "=")
+		key, value := strings.ToLower(key), strings.ToLower(raw)
+		var err error
+		switch key {
+		case "depth":
+			c.ModeOptions.Depth, err = strconv.Atoi(value)
+			if err == nil && c.ModeOptions.Depth < 0 {
+				err = errors.New("must be >= 0")
+			}
+		case "threshold":
+			c.ModeOptions.Threshold, err = strconv.ParseFloat(value, 64)
+			if err == nil && c.ModeOptions.Threshold < 0 {
+				err = errors.New("must be >= 0")
+			}
+		case "scale":
+			c.ModeOptions.Scale, err = strconv.Atoi(value)
+			if err == nil && c.ModeOptions.Scale < 1 {
+				err = errors.New("must be > 0")
+			}
+		case "colors":
+			c.ModeOptions.Colors, err = strconv.Atoi(value)
+			if err == nil && (c.ModeOptions.Colors < 2 || c.ModeOptions.Colors > 256) {
+				err = errors.New("must be in 2..256 range")
+			}
+		case "palette":
+			// file path keeps its case
+			c.ModeOptions.Palette = raw
+			if raw == "" {
+				err = errors.New("palette file path is empty")
+			}
+		case "metric":
+			c.ModeOptions.Metric = ColorMetric(value)
+			if !slices.Contains(COLOR_METRICS, c.ModeOptions.Metric) {
+				err = errors.New("available metrics: rgb, ciede2000, oklab")
+			}
+		case "reference":
+			c.ModeOptions.Reference = raw
+			if raw == "" {
+				err = errors.New("reference image path is empty")
+			}
+		case "transfer":
+			c.ModeOptions.Transfer = TransferMethod(value)
+			if !slices.Contains(TRANSFER_METHODS, c.ModeOptions.Transfer) {
+				err = errors.New("available methods: reinhard, palette")
+			}
+		case "min-region":
+			c.ModeOptions.MinRegion, err = strconv.Atoi(value)
+			if err == nil && c.ModeOptions.MinRegion < 0 {
+				err = errors.New("must be >= 0")
+			}
+		case "dither":
+			c.ModeOptions.Dither = DitherMethod(value)
+			if !slices.Contains(DITHER_METHODS, c.ModeOptions.Dither) {
+				err = errors.New("available methods: floyd-steinberg, atkinson, bayer, none")
+			}
+		default:
+			return errors.New("unknown mode option: " + key + ". " + syntax)
+		}
+		if err != nil {
+			return errors.New("wrong mode option value: " + arg + ", " + err.Error())
+		}
+	}
+	return nil
+}
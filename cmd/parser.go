@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"errors"
+	"log/slog"
 	"os"
 	"regexp"
 	"sort"
@@ -10,10 +11,27 @@ import (
 const flagRE = `^-[a-zA-Z]$` // single letter only
 
 func ParseArgs() (Config, []error) {
-	args := os.Args[1:]
+	command, args, err := SplitCommand(os.Args[1:])
+	if err != nil {
+		return Config{}, []error{ err }
+	}
 	flagsPos := FindAllFlags(args)
 
-	return MakeConfig(args, flagsPos)
+	// file values first, then environment, flags override both
+	config := Config{ Command: command }
+	errs := config.loadConfigFile(args, flagsPos, os.LookupEnv)
+	errs = append(errs, config.applyEnv(os.LookupEnv)...)
+	errs = append(errs, config.applyEarlyFlags(args, flagsPos)...)
+
+	// logger is ready before inputs are discovered
+	SetupLogging(config)
+	if config.configFile != "" {
+		slog.Debug("config file applied", "path", config.configFile)
+	}
+	errs = append(errs, config.applyLateFlags(args, flagsPos)...)
+	slog.Debug("arguments parsed", "command", config.Command, "inputs", len(config.InputFiles))
+
+	return config, errs
 }
 
 func FindAllFlags(args []string) (flagsPos map[int]string) {
@@ -31,6 +49,18 @@ func FindAllFlags(args []string) (flagsPos map[int]string) {
 }
 
 func MakeConfig(args []string, flagsPos map[int]string) (Config, []error) {
+	return MakeCommandConfig("", args, flagsPos)
+}
+
+func MakeCommandConfig(command Command, args []string, flagsPos map[int]string) (Config, []error) {
+	config := Config{ Command: command }
+	errs := config.applyFlags(args, flagsPos)
+
+	return config, errs
+}
+
+// flagArgs splits args into values following each flag, in flags order
+func flagArgs(args []string, flagsPos map[int]string) ([]string, [][]string) {
 	// ensure flags order
 	positions := make([]int, 0, len(flagsPos))
 	for pos := range flagsPos {
@@ -38,12 +68,9 @@ func MakeConfig(args []string, flagsPos map[int]string) (Config, []error) {
 	}
 	sort.Ints(positions)
 
-	// make config
-	config := Config{}
-	var err error
-	errs := make([]error, 0)
+	flags := make([]string, len(positions))
+	values := make([][]string, len(positions))
 	for i, pos := range positions {
-		err = nil
 		firstArg := pos + 1
 		var lastArg int
 		if i == len(positions) - 1 {
@@ -51,24 +78,115 @@ func MakeConfig(args []string, flagsPos map[int]string) (Config, []error) {
 		} else {
 			lastArg = positions[i + 1]
 		}
-		argSlice := args[firstArg:lastArg]
-		switch flag := flagsPos[pos]; flag {
+		flags[i] = flagsPos[pos]
+		values[i] = args[firstArg:lastArg]
+	}
+
+	return flags, values
+}
+
+func (c *Config) applyFlags(args []string, flagsPos map[int]string) []error {
+	errs := c.applyEarlyFlags(args, flagsPos)
+	return append(errs, c.applyLateFlags(args, flagsPos)...)
+}
+
+// applyEarlyFlags sets discovery filters and logging, so they apply to -i / -f regardless of order
+func (c *Config) applyEarlyFlags(args []string, flagsPos map[int]string) []error {
+	var err error
+	errs := make([]error, 0)
+	flags, values := flagArgs(args, flagsPos)
+	// flags replace patterns from file & env, repeated flags add up
+	replaced := make(map[string]bool)
+
+	for i, flag := range flags {
+		err = nil
+		if !isEarlyFlag(flag) { continue }
+		if !c.allowsFlag(flag) {
+			errs = append(errs, unsupportedFlagError(flag, c.commandOrDefault()))
+			continue
+		}
+		switch flag {
+		case "-n":
+			if !replaced[flag] {
+				c.Include, replaced[flag] = nil, true
+			}
+			err = c.setInclude(values[i])
+		case "-x":
+			if !replaced[flag] {
+				c.Exclude, replaced[flag] = nil, true
+			}
+			err = c.setExclude(values[i])
+		case "-d":
+			err = c.setMaxDepth(values[i])
+		case "-l":
+			err = c.setLogging(values[i])
+		}
+		if err != nil { errs = append(errs, err) }
+	}
+
+	return errs
+}
+
+func (c *Config) applyLateFlags(args []string, flagsPos map[int]string) []error {
+	var err error
+	errs := make([]error, 0)
+	flags, values := flagArgs(args, flagsPos)
+
+	for i, flag := range flags {
+		err = nil
+		if isEarlyFlag(flag) { continue }
+		argSlice := values[i]
+		if isKnownFlag(flag) && !c.allowsFlag(flag) {
+			errs = append(errs, unsupportedFlagError(flag, c.commandOrDefault()))
+			continue
+		}
+		switch flag {
 		case "-i":
-			config.addInputFiles(argSlice)
+			err = c.addInputFiles(argSlice)
 		case "-g":
-			err = config.setGrid(argSlice)
+			err = c.setGrid(argSlice)
+		case "-q":
+			err = c.setSquareGrid(argSlice)
 		case "-r":
-			err = config.setOutputResolution(argSlice)
+			err = c.setOutputResolution(argSlice)
 		case "-f":
-			err = config.addFolders(argSlice)
+			err = c.addFolders(argSlice)
 		case "-m":
-			config.setModes(argSlice)
+			c.setModes(argSlice)
+		case "-s":
+			err = c.setGridStyle(argSlice)
+		case "-k":
+			err = c.setModeOptions(argSlice)
+		case "-t":
+			err = c.setTessellation(argSlice)
+		case "-e":
+			c.setExportFormats(argSlice)
+		case "-o":
+			err = c.setOutputDir(argSlice)
+		case "-j":
+			err = c.setReportPath(argSlice)
+		case "-v":
+			err = c.setVerbosity(argSlice)
+		case "-w":
+			err = c.setWorkers(argSlice)
+		case "-p":
+			err = c.setPreset(argSlice)
+		case "-c":
+			// config file is loaded before flags are applied
 		default:
 			err = errors.New("Unknown flag: " + flag + " (skipped)")
 		}
 		if err != nil { errs = append(errs, err) }
 	}
+	c.dedupeInputFiles()
 
-	return config, errs
+	return errs
 }
 
+func unsupportedFlagError(flag string, command Command) error {
+	return errors.New("flag " + flag + " is not supported by " + string(command) + " command (skipped)")
+}
+
+func isEarlyFlag(flag string) bool {
+	return flag == "-n" || flag == "-x" || flag == "-d" || flag == "-l"
+}
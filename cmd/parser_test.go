@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -121,4 +123,17 @@ func TestMakeConfig_InputsFromFolder(t *testing.T) {
 	
 	assert.Len(got.InputFiles, 4)
 	assert.Len(errs, 0)
-}
\ No newline at end of file
+}
+func TestMakeConfig_DuplicateInputs(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.png")
+	assert.Nil(os.WriteFile(path, []byte{}, 0644))
+	args := []string{ "-i", path, dir + "/./a.png", "-f", dir }
+	flagsPos := FindAllFlags(args)
+
+	got, errs := MakeConfig(args, flagsPos)
+
+	assert.Len(errs, 0)
+	assert.Equal([]string{ path }, got.InputFiles)
+}
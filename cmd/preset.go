@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+type Preset struct {
+	GridRows int
+	GridCols int
+
+	OutputWidth  int
+	OutputHeight int
+
+	Modes []string
+}
+
+var PRESETS = map[string]Preset {
+	"instagram-square":   { GridRows: 6, GridCols: 6, OutputWidth: 1080, OutputHeight: 1080, Modes: []string{ "PALLETE" } },
+	"instagram-portrait": { GridRows: 5, GridCols: 4, OutputWidth: 1080, OutputHeight: 1350, Modes: []string{ "PALLETE" } },
+	"story":              { GridRows: 8, GridCols: 1, OutputWidth: 1080, OutputHeight: 1920, Modes: []string{ "PALLETE" } },
+	"banner":             { GridRows: 1, GridCols: 8, OutputWidth: 1500, OutputHeight: 500, Modes: []string{ "PALLETE" } },
+}
+
+// preset as written in config file, e.g. { "grid": "6x6", "resolution": "1080x1080" }
+type FilePreset struct {
+	Grid       string   `json:"grid"`
+	Resolution string   `json:"resolution"`
+	Modes      []string `json:"modes"`
+}
+
+func (fp FilePreset) toPreset() (Preset, error) {
+	tmp := Config{}
+	if fp.Grid != "" {
+		if err := tmp.setGrid([]string{ fp.Grid }); err != nil { return Preset{}, err }
+	}
+	if fp.Resolution != "" {
+		if err := tmp.setOutputResolution([]string{ fp.Resolution }); err != nil { return Preset{}, err }
+	}
+	tmp.setModes(fp.Modes)
+
+	return Preset{
+		GridRows: tmp.GridRows,
+		GridCols: tmp.GridCols,
+		OutputWidth: tmp.OutputWidth,
+		OutputHeight: tmp.OutputHeight,
+		Modes: tmp.Modes,
+	}, nil
+}
+
+// lookupPreset prefers user-defined presets over built-in ones with the same name
+func (c *Config) lookupPreset() (Preset, bool) {
+	name := strings.ToLower(c.Preset)
+	if p, ok := c.Presets[name]; ok {
+		return p, true
+	}
+	p, ok := PRESETS[name]
+	return p, ok
+}
+
+// applyPreset fills only values which were not set explicitly
+func (c *Config) applyPreset() {
+	if c.Preset == "" { return }
+	p, ok := c.lookupPreset()
+	if !ok { return }
+
+	if !c.gridSet && p.GridRows > 0 && p.GridCols > 0 {
+		c.GridRows, c.GridCols = p.GridRows, p.GridCols
+		c.gridSet = true
+	}
+	if len(c.Modes) == 0 && len(p.Modes) > 0 {
+		c.Modes = append([]string{}, p.Modes...)
+	}
+	// resolution is a pallete option, skip it when modes were picked without pallete
+	usesPallete := len(c.Modes) == 0 || c.hasMode(PALLETE)
+	if usesPallete && !c.hasOutputResolution() && p.OutputWidth > 0 && p.OutputHeight > 0 {
+		c.OutputWidth, c.OutputHeight = p.OutputWidth, p.OutputHeight
+	}
+}
+
+func (c *Config) setPreset(args []string) error {
+	if len(args) != 1 {
+		return errors.New("preset flag expects exactly one name. syntax: -p instagram-square")
+	}
+	c.Preset = strings.ToLower(args[0])
+	return nil
+}
+
+func (c *Config) presetNames() []string {
+	names := make([]string, 0, len(PRESETS) + len(c.Presets))
+	for name := range PRESETS {
+		names = append(names, name)
+	}
+	for name := range c.Presets {
+		if _, builtin := PRESETS[name]; !builtin {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDefaults_Preset(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{ Preset: "banner" }
+
+	config.SetDefaults()
+
+	assert.Equal(1, config.GridRows)
+	assert.Equal(8, config.GridCols)
+	assert.Equal(1500, config.OutputWidth)
+	assert.Equal(500, config.OutputHeight)
+	assert.Equal([]string{ "PALLETE" }, config.Modes)
+}
+
+func TestSetDefaults_PresetKeepsExplicitValues(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{ Preset: "instagram-square", GridRows: 3, GridCols: 3, gridSet: true, Modes: []string{ "GRID" } }
+
+	config.SetDefaults()
+
+	assert.Equal(3, config.GridRows)
+	assert.Equal(3, config.GridCols)
+	assert.Equal(0, config.OutputWidth)
+	assert.Equal([]string{ "GRID" }, config.Modes)
+}
+
+func TestSetDefaults_UserPresetOverridesBuiltin(t *testing.T) {
+	config := Config{
+		Preset: "banner",
+		Presets: map[string]Preset{ "banner": { GridRows: 2, GridCols: 10 } },
+	}
+
+	config.SetDefaults()
+
+	assert.Equal(t, 2, config.GridRows)
+	assert.Equal(t, 10, config.GridCols)
+}
+
+func TestValidate_UnknownPreset(t *testing.T) {
+	config := Config{ InputFiles: []string{ "input.jpg" }, Preset: "poster" }
+	config.SetDefaults()
+
+	errs := config.Validate()
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "unknown preset: poster")
+}
+
+func TestFilePreset_ToPreset(t *testing.T) {
+	fp := FilePreset{ Grid: "4x4", Resolution: "256x128", Modes: []string{ "pallete" } }
+
+	p, err := fp.toPreset()
+
+	assert.Nil(t, err)
+	assert.Equal(t, Preset{ GridRows: 4, GridCols: 4, OutputWidth: 256, OutputHeight: 128, Modes: []string{ "PALLETE" } }, p)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// "auto" picks black or white line per tile, whichever contrasts with tile average color
+const AUTO_COLOR = "auto"
+
+type GridStyle struct {
+	Color   string  // hex (#rgb, #rrggbb, #rrggbbaa) or auto
+	Width   int     // line thickness in pixels
+	Opacity float64 // 0..1, blended over source
+	Dash    int     // dash length in pixels, 0 - solid line
+	Border  bool    // outline image edges too
+
+	Labels     bool // column letters & row numbers in a margin around the image
+	CellLabels bool // tile name (e.g. C7) inside every tile
+}
+
+var DEFAULT_GRID_STYLE = GridStyle{ Color: "#000000", Width: 1, Opacity: 1 }
+
+func ParseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{ hex[0], hex[0], hex[1], hex[1], hex[2], hex[2] })
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.RGBA{}, errors.New("invalid color: " + s + ". acceptable formats: #rgb, #rrggbb, #rrggbbaa")
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, errors.New("invalid color: " + s + ". acceptable formats: #rgb, #rrggbb, #rrggbbaa")
+	}
+	return color.RGBA{ uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v) }, nil
+}
+
+// setGridStyle updates style from key=value pairs, e.g. -s color=#ff0000 width=2 dash=4 border
+func (c *Config) setGridStyle(args []string) error {
+	const syntax = "syntax: -s color=#rrggbb|auto width=1 opacity=0.5 dash=4 border labels cell-labels"
+	if len(args) == 0 {
+		return errors.New("not enough arguments for grid style. " + syntax)
+	}
+	if c.GridStyle == (GridStyle{}) {
+		c.GridStyle = DEFAULT_GRID_STYLE
+	}
+
+	for _, arg := range args {
+		key, value, _ := strings.Cut(strings.ToLower(arg), "=")
+		var err error
+		switch key {
+		case "color":
+			if value != AUTO_COLOR {
+				_, err = ParseHexColor(value)
+			}
+			c.GridStyle.Color = value
+		case "width":
+			c.GridStyle.Width, err = strconv.Atoi(value)
+			if err == nil && c.GridStyle.Width < 1 {
+				err = errors.New("must be > 0")
+			}
+		case "opacity":
+			c.GridStyle.Opacity, err = strconv.ParseFloat(value, 64)
+			if err == nil && (c.GridStyle.Opacity < 0 || c.GridStyle.Opacity > 1) {
+				err = errors.New("must be in 0..1 range")
+			}
+		case "dash":
+			c.GridStyle.Dash, err = strconv.Atoi(value)
+			if err == nil && c.GridStyle.Dash < 0 {
+				err = errors.New("must be >= 0")
+			}
+		case "border":
+			c.GridStyle.Border = value == "" || value == "true"
+		case "labels":
+			c.GridStyle.Labels = value == "" || value == "true"
+		case "cell-labels":
+			c.GridStyle.CellLabels = value == "" || value == "true"
+		default:
+			return errors.New("unknown grid style option: " + key + ". " + syntax)
+		}
+		if err != nil {
+			return errors.New("wrong grid style value: " + arg + ", " + err.Error())
+		}
+	}
+	return nil
+}
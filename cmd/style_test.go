@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHexColor(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := ParseHexColor("#ff8000")
+	assert.Nil(err)
+	assert.Equal(color.RGBA{ 255, 128, 0, 255 }, c)
+
+	c, err = ParseHexColor("fff")
+	assert.Nil(err)
+	assert.Equal(color.RGBA{ 255, 255, 255, 255 }, c)
+
+	c, err = ParseHexColor("#00000080")
+	assert.Nil(err)
+	assert.Equal(color.RGBA{ 0, 0, 0, 128 }, c)
+
+	_, err = ParseHexColor("#12345")
+	assert.ErrorContains(err, "invalid color")
+}
+
+func TestSetGridStyle(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	err := config.setGridStyle([]string{ "color=auto", "width=3", "opacity=0.25", "dash=4", "border" })
+
+	assert.Nil(err)
+	assert.Equal(GridStyle{ Color: "auto", Width: 3, Opacity: 0.25, Dash: 4, Border: true }, config.GridStyle)
+}
+
+func TestSetGridStyle_KeepsDefaults(t *testing.T) {
+	config := Config{}
+
+	config.setGridStyle([]string{ "width=2" })
+
+	assert.Equal(t, GridStyle{ Color: "#000000", Width: 2, Opacity: 1 }, config.GridStyle)
+}
+
+func TestSetGridStyle_InvalidValues(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{}
+
+	assert.ErrorContains(config.setGridStyle([]string{ "color=#zz0000" }), "wrong grid style value")
+	assert.ErrorContains(config.setGridStyle([]string{ "opacity=2" }), "0..1 range")
+	assert.ErrorContains(config.setGridStyle([]string{ "shadow=1" }), "unknown grid style option")
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+)
+
+// shape of grid cells, cell size comes from the rect grid (-g / -q)
+type Tessellation string
+const (
+	RECT     Tessellation = "rect"
+	HEX      Tessellation = "hex"      // pointy-top hexagons, odd rows shifted by half a cell
+	TRIANGLE Tessellation = "triangle" // alternating up / down triangles
+	BRICK    Tessellation = "brick"    // rectangles, odd rows shifted by half a cell
+	VORONOI  Tessellation = "voronoi"  // cells around seeds sampled in every grid cell
+	SLIC     Tessellation = "slic"     // superpixels following color edges
+)
+
+var TESSELLATIONS = []Tessellation{ RECT, HEX, TRIANGLE, BRICK, VORONOI, SLIC }
+
+// IsTessellated is true for polygon cells, rect grid uses plain tiles
+func (c *Config) IsTessellated() bool {
+	return c.Tessellation != "" && c.Tessellation != RECT
+}
+
+func (c *Config) setTessellation(args []string) error {
+	const syntax = "syntax: -t rect|hex|triangle|brick|voronoi|slic"
+	if len(args) != 1 {
+		return errors.New("wrong arguments for tessellation. " + syntax)
+	}
+	t := Tessellation(strings.ToLower(args[0]))
+	for _, v := range TESSELLATIONS {
+		if t == v {
+			c.Tessellation = t
+			return nil
+		}
+	}
+	return errors.New("unknown tessellation: " + args[0] + ". " + syntax)
+}
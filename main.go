@@ -3,46 +3,77 @@ package main
 import (
 	"color-pallete/cmd"
 	"color-pallete/services"
-	"fmt"
+	"errors"
+	"log/slog"
 	"os"
-	"time"
 )
 
-func main() {
-	Bench(Run)
-}
-
-func Bench(fn func()) {
-	s := time.Now()
-	fn()
-	f := time.Now()
+const (
+	EXIT_OK              = 0
+	EXIT_CONFIG_ERROR    = 2
+	EXIT_PARTIAL_FAILURE = 3
+	EXIT_TOTAL_FAILURE   = 4
+)
 
-	elapsed := float64(f.UnixMilli() - s.UnixMilli()) / 1000
-	fmt.Println("time:", elapsed)
+func main() {
+	os.Exit(Run())
 }
 
-func Run() {
+func Run() int {
 	config, errs := cmd.ParseArgs()
 
 	for _, err := range errs {
-		fmt.Printf("args parsing error: %s\n", err.Error())
+		slog.Error("invalid arguments", "err", err)
 	}
 	if len(errs) > 0 {
-		os.Exit(1)
+		return EXIT_CONFIG_ERROR
 	}
 
 	config.SetDefaults()
 	errs = config.Validate()
 
 	for _, err := range errs {
-		fmt.Printf("configuration error: %s\n", err.Error())
+		slog.Error("invalid configuration", "err", err)
 	}
 	if len(errs) > 0 {
-		os.Exit(1)
+		return EXIT_CONFIG_ERROR
 	}
-	
-	errs = services.ProcessFiles(config)
-	for _, err := range errs {
-		fmt.Printf("image processing error: %s\n", err.Error())
+
+	var summary services.Summary
+	switch config.Command {
+	case cmd.EXTRACT:
+		summary = services.ExtractFiles(config)
+	case cmd.EXPORT:
+		summary = services.ExportFiles(config)
+	case cmd.INSPECT:
+		summary = services.InspectFiles(config)
+	default:
+		summary = services.ProcessFiles(config)
+	}
+	for _, err := range summary.Errors {
+		logProcessError(err)
+	}
+	slog.Info("run finished", "command", config.Command, "total", summary.Total, "failed", summary.Failed)
+
+	return ExitCode(summary)
+}
+
+func logProcessError(err error) {
+	var pe *services.ProcessError
+	if errors.As(err, &pe) {
+		slog.Error("image processing failed", "path", pe.Path, "mode", pe.Mode, "stage", pe.Stage, "err", pe.Err)
+		return
+	}
+	slog.Error("image processing failed", "err", err)
+}
+
+func ExitCode(summary services.Summary) int {
+	switch {
+	case summary.Total > 0 && summary.Failed >= summary.Total:
+		return EXIT_TOTAL_FAILURE
+	case len(summary.Errors) > 0:
+		return EXIT_PARTIAL_FAILURE
+	default:
+		return EXIT_OK
 	}
 }
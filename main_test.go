@@ -0,0 +1,17 @@
+package main
+
+import (
+	"color-pallete/services"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	fileErr := &services.ProcessError{ Path: "a.jpg", Mode: "GRID", Stage: services.DECODE, Err: errors.New("bad") }
+
+	assert.Equal(t, EXIT_OK, ExitCode(services.Summary{ Total: 2 }))
+	assert.Equal(t, EXIT_PARTIAL_FAILURE, ExitCode(services.Summary{ Total: 2, Failed: 1, Errors: []error{ fileErr } }))
+	assert.Equal(t, EXIT_TOTAL_FAILURE, ExitCode(services.Summary{ Total: 1, Failed: 1, Errors: []error{ fileErr } }))
+}
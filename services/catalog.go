@@ -0,0 +1,345 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+)
+
+// CatalogColor is a purchasable color, e.g. embroidery thread
+type CatalogColor struct {
+	Code  string
+	Name  string
+	Color color.RGBA
+}
+
+// Catalog is a fixed set of colors output is limited to
+type Catalog struct {
+	Name   string
+	Colors []CatalogColor
+}
+
+func (c Catalog) palette() []color.RGBA {
+	palette := make([]color.RGBA, len(c.Colors))
+	for i, cc := range c.Colors {
+		palette[i] = cc.Color
+	}
+	return palette
+}
+
+// CatalogPattern is a tile grid where every tile is a single catalog color, e.g. a stitch or a brick
+type CatalogPattern struct {
+	Catalog    string
+	Rows, Cols int
+	Colors     []CatalogColor // used colors, most used first
+	Counts     []int          // tiles of every color
+	Cells      []int          // color of every tile, row-major
+}
+
+// MatchCatalog maps tile averages to catalog colors, limit > 0 caps number of colors:
+// averages are quantized first and every palette color is matched to the nearest catalog one
+func MatchCatalog(img image.Image, tiles []Tile, catalog Catalog, limit int, metric cmd.ColorMetric) CatalogPattern {
+	rows, cols := gridShape(tiles)
+	p := CatalogPattern{ Catalog: catalog.Name, Rows: rows, Cols: cols, Cells: make([]int, len(tiles)) }
+	if len(tiles) == 0 {
+		return p
+	}
+
+	averages := image.NewRGBA(image.Rect(0, 0, cols, rows))
+	for i, t := range tiles {
+		averages.SetRGBA(i % cols, i / cols, AverageColor(img, t))
+	}
+
+	candidates := catalog.Colors
+	if limit > 0 {
+		catalogMatcher := newPaletteMatcher(catalog.palette(), metric)
+		used := make(map[color.RGBA]bool)
+		for _, c := range QuantizePalette(averages, limit) {
+			used[catalogMatcher.nearest(c)] = true
+		}
+		candidates = make([]CatalogColor, 0, len(used))
+		for _, cc := range catalog.Colors {
+			if used[cc.Color] {
+				used[cc.Color] = false
+				candidates = append(candidates, cc)
+			}
+		}
+	}
+
+	palette := Catalog{ Colors: candidates }.palette()
+	matcher := newPaletteMatcher(palette, metric)
+	indexOf := make(map[color.RGBA]int, len(palette))
+	for i := len(palette) - 1; i >= 0; i-- {
+		indexOf[palette[i]] = i
+	}
+	counts := make([]int, len(candidates))
+	for i := range tiles {
+		p.Cells[i] = indexOf[matcher.nearest(averages.RGBAAt(i % cols, i / cols))]
+		counts[p.Cells[i]]++
+	}
+
+	// unused colors are dropped, the rest is ordered by usage
+	order := make([]int, 0, len(candidates))
+	for i := range candidates {
+		if counts[i] > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return counts[order[a]] > counts[order[b]] })
+	remap := make([]int, len(candidates))
+	for i, c := range order {
+		remap[c] = i
+		p.Colors = append(p.Colors, candidates[c])
+		p.Counts = append(p.Counts, counts[c])
+	}
+	for i := range p.Cells {
+		p.Cells[i] = remap[p.Cells[i]]
+	}
+	return p
+}
+
+// withLegend puts chart on white canvas above a legend of swatches with labels,
+// canvas gets wider when chart is narrower than a legend entry
+func withLegend(chart *image.RGBA, labels []string, swatch int, drawSwatch func(dst *image.RGBA, x, y, i int)) *image.RGBA {
+	const scale, pad = 2, 8
+	w, h := chart.Bounds().Dx(), chart.Bounds().Dy()
+	entryW := 1
+	for _, label := range labels {
+		entryW = max(entryW, swatch + pad + textWidth(label, scale) + 2 * pad)
+	}
+	width := max(w, entryW + pad)
+	perRow := max(1, (width - pad) / entryW)
+	legendRows := (len(labels) + perRow - 1) / perRow
+
+	out := image.NewRGBA(image.Rect(0, 0, width, h + pad + legendRows * (swatch + pad)))
+	draw.Draw(out, out.Bounds(), &image.Uniform{ color.White }, image.Point{}, draw.Src)
+	draw.Draw(out, chart.Bounds(), chart, chart.Bounds().Min, draw.Src)
+	for i, label := range labels {
+		x, y := pad + (i % perRow) * entryW, h + pad + (i / perRow) * (swatch + pad)
+		drawSwatch(out, x, y, i)
+		drawText(out, x + swatch + pad, y + (swatch - textHeight(scale)) / 2, label, scale, color.RGBA{ 0, 0, 0, 255 })
+	}
+	return out
+}
+
+// DMC_FLOSS is a subset of DMC six-strand embroidery floss, rgb values are approximate screen colors
+var DMC_FLOSS = Catalog{
+	Name: "DMC",
+	Colors: []CatalogColor{
+		{ "B5200", "Snow White", color.RGBA{ 0xff, 0xff, 0xff, 255 } },
+		{ "White", "White", color.RGBA{ 0xfc, 0xfb, 0xf8, 255 } },
+		{ "3865", "Winter White", color.RGBA{ 0xf9, 0xf7, 0xf1, 255 } },
+		{ "Ecru", "Ecru", color.RGBA{ 0xf0, 0xea, 0xda, 255 } },
+		{ "3823", "Yellow Ultra Pale", color.RGBA{ 0xff, 0xfd, 0xe3, 255 } },
+		{ "310", "Black", color.RGBA{ 0x00, 0x00, 0x00, 255 } },
+		{ "3371", "Black Brown", color.RGBA{ 0x1e, 0x11, 0x08, 255 } },
+		{ "762", "Pearl Gray Vy Lt", color.RGBA{ 0xec, 0xec, 0xec, 255 } },
+		{ "415", "Pearl Gray", color.RGBA{ 0xd3, 0xd3, 0xd6, 255 } },
+		{ "318", "Steel Gray Lt", color.RGBA{ 0xab, 0xab, 0xab, 255 } },
+		{ "414", "Steel Gray Dk", color.RGBA{ 0x8c, 0x8c, 0x8c, 255 } },
+		{ "317", "Pewter Gray", color.RGBA{ 0x6c, 0x6c, 0x6c, 255 } },
+		{ "413", "Pewter Gray Dk", color.RGBA{ 0x56, 0x56, 0x56, 255 } },
+		{ "3799", "Pewter Gray Vy Dk", color.RGBA{ 0x42, 0x42, 0x42, 255 } },
+		{ "3072", "Beaver Gray Vy Lt", color.RGBA{ 0xe6, 0xe8, 0xe8, 255 } },
+		{ "648", "Beaver Gray Lt", color.RGBA{ 0xbc, 0xb4, 0xac, 255 } },
+		{ "3024", "Brown Gray Vy Lt", color.RGBA{ 0xeb, 0xea, 0xe7, 255 } },
+		{ "3023", "Brown Gray Lt", color.RGBA{ 0xb1, 0xaa, 0x97, 255 } },
+		{ "3022", "Brown Gray Med", color.RGBA{ 0x8e, 0x90, 0x78, 255 } },
+		{ "3021", "Brown Gray Vy Dk", color.RGBA{ 0x4f, 0x4b, 0x41, 255 } },
+		{ "321", "Red", color.RGBA{ 0xc7, 0x2b, 0x3b, 255 } },
+		{ "304", "Red Medium", color.RGBA{ 0xb7, 0x1f, 0x33, 255 } },
+		{ "498", "Red Dark", color.RGBA{ 0xa7, 0x13, 0x2b, 255 } },
+		{ "666", "Bright Red", color.RGBA{ 0xe3, 0x1d, 0x42, 255 } },
+		{ "815", "Garnet Medium", color.RGBA{ 0x87, 0x07, 0x1f, 255 } },
+		{ "817", "Coral Red Vy Dk", color.RGBA{ 0xbb, 0x05, 0x1f, 255 } },
+		{ "349", "Coral Dark", color.RGBA{ 0xd2, 0x10, 0x35, 255 } },
+		{ "350", "Coral Medium", color.RGBA{ 0xe0, 0x48, 0x48, 255 } },
+		{ "351", "Coral", color.RGBA{ 0xe9, 0x6a, 0x67, 255 } },
+		{ "352", "Coral Light", color.RGBA{ 0xfd, 0x9c, 0x97, 255 } },
+		{ "353", "Peach", color.RGBA{ 0xfe, 0xd7, 0xcc, 255 } },
+		{ "606", "Bright Orange Red", color.RGBA{ 0xfa, 0x32, 0x03, 255 } },
+		{ "608", "Bright Orange", color.RGBA{ 0xfd, 0x5d, 0x35, 255 } },
+		{ "740", "Tangerine", color.RGBA{ 0xff, 0x83, 0x13, 255 } },
+		{ "741", "Tangerine Medium", color.RGBA{ 0xff, 0xa3, 0x2b, 255 } },
+		{ "742", "Tangerine Light", color.RGBA{ 0xff, 0xbf, 0x57, 255 } },
+		{ "743", "Yellow Medium", color.RGBA{ 0xfe, 0xd3, 0x76, 255 } },
+		{ "744", "Yellow Pale", color.RGBA{ 0xff, 0xe7, 0x93, 255 } },
+		{ "307", "Lemon", color.RGBA{ 0xfd, 0xed, 0x54, 255 } },
+		{ "444", "Lemon Dark", color.RGBA{ 0xff, 0xd6, 0x00, 255 } },
+		{ "445", "Lemon Light", color.RGBA{ 0xff, 0xfb, 0x8b, 255 } },
+		{ "727", "Topaz Vy Lt", color.RGBA{ 0xff, 0xf1, 0xaf, 255 } },
+		{ "725", "Topaz Med Lt", color.RGBA{ 0xff, 0xc8, 0x40, 255 } },
+		{ "783", "Topaz Medium", color.RGBA{ 0xce, 0x91, 0x24, 255 } },
+		{ "782", "Topaz Dark", color.RGBA{ 0xae, 0x77, 0x20, 255 } },
+		{ "780", "Topaz Ult Vy Dk", color.RGBA{ 0x94, 0x63, 0x1a, 255 } },
+		{ "676", "Old Gold Lt", color.RGBA{ 0xe5, 0xce, 0x97, 255 } },
+		{ "729", "Old Gold Medium", color.RGBA{ 0xd0, 0xa5, 0x3e, 255 } },
+		{ "680", "Old Gold Dark", color.RGBA{ 0xbc, 0x8d, 0x0e, 255 } },
+		{ "3822", "Straw Light", color.RGBA{ 0xf6, 0xdc, 0x98, 255 } },
+		{ "3821", "Straw", color.RGBA{ 0xf3, 0xce, 0x75, 255 } },
+		{ "3820", "Straw Dark", color.RGBA{ 0xdf, 0xb6, 0x5f, 255 } },
+		{ "739", "Tan Ult Vy Lt", color.RGBA{ 0xf8, 0xe4, 0xc8, 255 } },
+		{ "738", "Tan Vy Lt", color.RGBA{ 0xec, 0xcc, 0x9e, 255 } },
+		{ "437", "Tan Light", color.RGBA{ 0xe4, 0xbb, 0x8e, 255 } },
+		{ "436", "Tan", color.RGBA{ 0xcb, 0x90, 0x51, 255 } },
+		{ "435", "Brown Vy Lt", color.RGBA{ 0xb8, 0x77, 0x48, 255 } },
+		{ "434", "Brown Light", color.RGBA{ 0x98, 0x5e, 0x33, 255 } },
+		{ "433", "Brown Medium", color.RGBA{ 0x7a, 0x45, 0x1f, 255 } },
+		{ "801", "Coffee Brown Dk", color.RGBA{ 0x65, 0x39, 0x19, 255 } },
+		{ "898", "Coffee Brown Vy Dk", color.RGBA{ 0x49, 0x2a, 0x13, 255 } },
+		{ "938", "Coffee Brown Ult Dk", color.RGBA{ 0x36, 0x1f, 0x0e, 255 } },
+		{ "3033", "Mocha Brown Vy Lt", color.RGBA{ 0xe3, 0xd8, 0xcc, 255 } },
+		{ "3782", "Mocha Brown Lt", color.RGBA{ 0x9a, 0x7c, 0x5c, 255 } },
+		{ "3781", "Mocha Brown Dk", color.RGBA{ 0x6b, 0x57, 0x43, 255 } },
+		{ "3031", "Mocha Brown Vy Dk", color.RGBA{ 0x4b, 0x3c, 0x2a, 255 } },
+		{ "613", "Drab Brown Vy Lt", color.RGBA{ 0xdc, 0xc4, 0xaa, 255 } },
+		{ "612", "Drab Brown Lt", color.RGBA{ 0xbc, 0x9a, 0x78, 255 } },
+		{ "610", "Drab Brown Dk", color.RGBA{ 0x79, 0x60, 0x47, 255 } },
+		{ "950", "Desert Sand Lt", color.RGBA{ 0xee, 0xd3, 0xc4, 255 } },
+		{ "3064", "Desert Sand", color.RGBA{ 0xc4, 0x8e, 0x70, 255 } },
+		{ "632", "Desert Sand Ult Vy Dk", color.RGBA{ 0x87, 0x55, 0x39, 255 } },
+		{ "3770", "Tawny Vy Lt", color.RGBA{ 0xff, 0xee, 0xe3, 255 } },
+		{ "951", "Tawny Light", color.RGBA{ 0xff, 0xe2, 0xcf, 255 } },
+		{ "945", "Tawny", color.RGBA{ 0xfb, 0xd5, 0xbb, 255 } },
+		{ "754", "Peach Light", color.RGBA{ 0xf7, 0xcb, 0xbf, 255 } },
+		{ "758", "Terra Cotta Vy Lt", color.RGBA{ 0xee, 0xaa, 0x9b, 255 } },
+		{ "3778", "Terra Cotta Lt", color.RGBA{ 0xd9, 0x89, 0x78, 255 } },
+		{ "356", "Terra Cotta Med", color.RGBA{ 0xc5, 0x6a, 0x5b, 255 } },
+		{ "3830", "Terra Cotta", color.RGBA{ 0xb9, 0x55, 0x44, 255 } },
+		{ "402", "Mahogany Vy Lt", color.RGBA{ 0xf7, 0xa7, 0x77, 255 } },
+		{ "3776", "Mahogany Light", color.RGBA{ 0xcf, 0x79, 0x39, 255 } },
+		{ "301", "Mahogany Medium", color.RGBA{ 0xb3, 0x5f, 0x2b, 255 } },
+		{ "400", "Mahogany Dark", color.RGBA{ 0x8f, 0x43, 0x0f, 255 } },
+		{ "300", "Mahogany Vy Dk", color.RGBA{ 0x6f, 0x2f, 0x00, 255 } },
+		{ "922", "Copper Light", color.RGBA{ 0xe2, 0x73, 0x23, 255 } },
+		{ "920", "Copper Medium", color.RGBA{ 0xac, 0x54, 0x14, 255 } },
+		{ "918", "Red Copper Dark", color.RGBA{ 0x82, 0x30, 0x12, 255 } },
+		{ "818", "Baby Pink", color.RGBA{ 0xff, 0xdf, 0xd9, 255 } },
+		{ "776", "Pink Medium", color.RGBA{ 0xfc, 0xb0, 0xb9, 255 } },
+		{ "3706", "Melon Medium", color.RGBA{ 0xff, 0xad, 0xbc, 255 } },
+		{ "3705", "Melon Dark", color.RGBA{ 0xff, 0x79, 0x92, 255 } },
+		{ "899", "Rose Medium", color.RGBA{ 0xf2, 0x76, 0x88, 255 } },
+		{ "335", "Rose", color.RGBA{ 0xee, 0x54, 0x6e, 255 } },
+		{ "326", "Rose Vy Dk", color.RGBA{ 0xb3, 0x3b, 0x4b, 255 } },
+		{ "605", "Cranberry Vy Lt", color.RGBA{ 0xff, 0xc0, 0xcd, 255 } },
+		{ "603", "Cranberry", color.RGBA{ 0xff, 0xa4, 0xbe, 255 } },
+		{ "602", "Cranberry Medium", color.RGBA{ 0xe2, 0x48, 0x74, 255 } },
+		{ "601", "Cranberry Dark", color.RGBA{ 0xd1, 0x28, 0x6a, 255 } },
+		{ "600", "Cranberry Vy Dk", color.RGBA{ 0xcd, 0x2f, 0x63, 255 } },
+		{ "3688", "Mauve Medium", color.RGBA{ 0xe7, 0xa9, 0xac, 255 } },
+		{ "3687", "Mauve", color.RGBA{ 0xc9, 0x6b, 0x70, 255 } },
+		{ "3685", "Mauve Vy Dk", color.RGBA{ 0x88, 0x15, 0x31, 255 } },
+		{ "554", "Violet Light", color.RGBA{ 0xdb, 0xb3, 0xcb, 255 } },
+		{ "553", "Violet", color.RGBA{ 0xa3, 0x63, 0x8b, 255 } },
+		{ "552", "Violet Medium", color.RGBA{ 0x80, 0x3a, 0x6b, 255 } },
+		{ "550", "Violet Vy Dk", color.RGBA{ 0x5c, 0x18, 0x4e, 255 } },
+		{ "211", "Lavender Light", color.RGBA{ 0xe3, 0xcb, 0xe3, 255 } },
+		{ "210", "Lavender Medium", color.RGBA{ 0xc3, 0x9f, 0xc3, 255 } },
+		{ "209", "Lavender Dark", color.RGBA{ 0xa3, 0x7b, 0xa7, 255 } },
+		{ "208", "Lavender Vy Dk", color.RGBA{ 0x83, 0x5b, 0x8b, 255 } },
+		{ "340", "Blue Violet Medium", color.RGBA{ 0xad, 0xa7, 0xc7, 255 } },
+		{ "3746", "Blue Violet Dark", color.RGBA{ 0x77, 0x6b, 0x98, 255 } },
+		{ "333", "Blue Violet Vy Dk", color.RGBA{ 0x5c, 0x54, 0x78, 255 } },
+		{ "775", "Baby Blue Vy Lt", color.RGBA{ 0xd9, 0xeb, 0xf1, 255 } },
+		{ "3755", "Baby Blue", color.RGBA{ 0x93, 0xb4, 0xce, 255 } },
+		{ "800", "Delft Blue Pale", color.RGBA{ 0xc0, 0xcc, 0xde, 255 } },
+		{ "809", "Delft Blue", color.RGBA{ 0x94, 0xa8, 0xc6, 255 } },
+		{ "799", "Delft Blue Medium", color.RGBA{ 0x74, 0x8e, 0xb6, 255 } },
+		{ "798", "Delft Blue Dark", color.RGBA{ 0x46, 0x6a, 0x8e, 255 } },
+		{ "797", "Royal Blue", color.RGBA{ 0x13, 0x47, 0x7d, 255 } },
+		{ "796", "Royal Blue Dark", color.RGBA{ 0x11, 0x41, 0x6d, 255 } },
+		{ "820", "Royal Blue Vy Dk", color.RGBA{ 0x0e, 0x36, 0x5c, 255 } },
+		{ "336", "Navy Blue", color.RGBA{ 0x25, 0x3b, 0x73, 255 } },
+		{ "823", "Navy Blue Dark", color.RGBA{ 0x21, 0x30, 0x63, 255 } },
+		{ "939", "Navy Blue Vy Dk", color.RGBA{ 0x1b, 0x16, 0x28, 255 } },
+		{ "828", "Sky Blue Vy Lt", color.RGBA{ 0xc5, 0xe8, 0xed, 255 } },
+		{ "827", "Blue Vy Lt", color.RGBA{ 0xbd, 0xdd, 0xed, 255 } },
+		{ "826", "Blue Medium", color.RGBA{ 0x6b, 0x9e, 0xbf, 255 } },
+		{ "824", "Blue Vy Dk", color.RGBA{ 0x39, 0x69, 0x87, 255 } },
+		{ "519", "Sky Blue", color.RGBA{ 0x7e, 0xb1, 0xc8, 255 } },
+		{ "517", "Wedgewood Dark", color.RGBA{ 0x3b, 0x76, 0x8f, 255 } },
+		{ "311", "Wedgewood Ult Vy Dk", color.RGBA{ 0x1c, 0x50, 0x66, 255 } },
+		{ "996", "Electric Blue Medium", color.RGBA{ 0x30, 0xc2, 0xec, 255 } },
+		{ "995", "Electric Blue Dark", color.RGBA{ 0x26, 0x96, 0xb6, 255 } },
+		{ "3846", "Bright Turquoise Lt", color.RGBA{ 0x06, 0xe3, 0xe6, 255 } },
+		{ "3844", "Bright Turquoise Dk", color.RGBA{ 0x12, 0xae, 0xba, 255 } },
+		{ "3814", "Aquamarine", color.RGBA{ 0x50, 0x8b, 0x7d, 255 } },
+		{ "3812", "Seagreen Vy Dk", color.RGBA{ 0x2f, 0x8c, 0x84, 255 } },
+		{ "502", "Blue Green", color.RGBA{ 0x5b, 0x90, 0x71, 255 } },
+		{ "501", "Blue Green Dark", color.RGBA{ 0x39, 0x6f, 0x52, 255 } },
+		{ "500", "Blue Green Vy Dk", color.RGBA{ 0x04, 0x4d, 0x33, 255 } },
+		{ "955", "Nile Green Light", color.RGBA{ 0xa2, 0xd6, 0xad, 255 } },
+		{ "954", "Nile Green", color.RGBA{ 0x88, 0xba, 0x91, 255 } },
+		{ "912", "Emerald Green Lt", color.RGBA{ 0x1b, 0x9d, 0x6b, 255 } },
+		{ "911", "Emerald Green Med", color.RGBA{ 0x18, 0x90, 0x65, 255 } },
+		{ "910", "Emerald Green Dark", color.RGBA{ 0x18, 0x7e, 0x56, 255 } },
+		{ "909", "Emerald Green Vy Dk", color.RGBA{ 0x15, 0x6f, 0x49, 255 } },
+		{ "369", "Pistachio Green Vy Lt", color.RGBA{ 0xd7, 0xed, 0xcc, 255 } },
+		{ "368", "Pistachio Green Lt", color.RGBA{ 0xa6, 0xc2, 0x98, 255 } },
+		{ "367", "Pistachio Green Dk", color.RGBA{ 0x61, 0x7a, 0x52, 255 } },
+		{ "319", "Pistachio Grn Vy Dk", color.RGBA{ 0x20, 0x5f, 0x2e, 255 } },
+		{ "890", "Pistachio Grn Ult Vy Dk", color.RGBA{ 0x17, 0x49, 0x23, 255 } },
+		{ "3348", "Yellow Green Lt", color.RGBA{ 0xcc, 0xd9, 0xb1, 255 } },
+		{ "3347", "Yellow Green Med", color.RGBA{ 0x71, 0x93, 0x5c, 255 } },
+		{ "3346", "Hunter Green", color.RGBA{ 0x40, 0x6a, 0x3a, 255 } },
+		{ "3345", "Hunter Green Vy Dk", color.RGBA{ 0x1b, 0x59, 0x15, 255 } },
+		{ "907", "Parrot Green Lt", color.RGBA{ 0xc7, 0xe6, 0x66, 255 } },
+		{ "906", "Parrot Green Medium", color.RGBA{ 0x7f, 0xb3, 0x35, 255 } },
+		{ "905", "Parrot Green Dark", color.RGBA{ 0x62, 0x9d, 0x1f, 255 } },
+		{ "904", "Parrot Green Vy Dk", color.RGBA{ 0x55, 0x78, 0x22, 255 } },
+		{ "704", "Chartreuse Bright", color.RGBA{ 0x9e, 0xcf, 0x34, 255 } },
+		{ "703", "Chartreuse", color.RGBA{ 0x7b, 0xb5, 0x47, 255 } },
+		{ "702", "Kelly Green", color.RGBA{ 0x47, 0x9b, 0x37, 255 } },
+		{ "701", "Green Light", color.RGBA{ 0x3f, 0x8f, 0x29, 255 } },
+		{ "700", "Green Bright", color.RGBA{ 0x07, 0x73, 0x1b, 255 } },
+		{ "699", "Green", color.RGBA{ 0x05, 0x65, 0x17, 255 } },
+	},
+}
+
+// BRICK_COLORS are solid colors of 1x1 plates and tiles, codes are LEGO color ids, rgb values are approximate
+var BRICK_COLORS = Catalog{
+	Name: "Brick",
+	Colors: []CatalogColor{
+		{ "1", "White", color.RGBA{ 0xf2, 0xf3, 0xf2, 255 } },
+		{ "194", "Light Bluish Gray", color.RGBA{ 0xa0, 0xa5, 0xa9, 255 } },
+		{ "199", "Dark Bluish Gray", color.RGBA{ 0x6c, 0x6e, 0x68, 255 } },
+		{ "26", "Black", color.RGBA{ 0x1b, 0x2a, 0x34, 255 } },
+		{ "21", "Red", color.RGBA{ 0xc9, 0x1a, 0x09, 255 } },
+		{ "154", "Dark Red", color.RGBA{ 0x72, 0x0e, 0x0f, 255 } },
+		{ "353", "Coral", color.RGBA{ 0xff, 0x69, 0x8f, 255 } },
+		{ "222", "Bright Pink", color.RGBA{ 0xe4, 0xad, 0xc8, 255 } },
+		{ "221", "Dark Pink", color.RGBA{ 0xc8, 0x70, 0xa0, 255 } },
+		{ "124", "Magenta", color.RGBA{ 0x92, 0x39, 0x78, 255 } },
+		{ "106", "Orange", color.RGBA{ 0xfe, 0x8a, 0x18, 255 } },
+		{ "38", "Dark Orange", color.RGBA{ 0xa9, 0x55, 0x00, 255 } },
+		{ "191", "Bright Light Orange", color.RGBA{ 0xf8, 0xbb, 0x3d, 255 } },
+		{ "24", "Yellow", color.RGBA{ 0xf2, 0xcd, 0x37, 255 } },
+		{ "226", "Bright Light Yellow", color.RGBA{ 0xff, 0xf0, 0x3a, 255 } },
+		{ "5", "Tan", color.RGBA{ 0xe4, 0xcd, 0x9e, 255 } },
+		{ "138", "Dark Tan", color.RGBA{ 0x95, 0x8a, 0x73, 255 } },
+		{ "283", "Light Nougat", color.RGBA{ 0xfc, 0xc3, 0x9e, 255 } },
+		{ "18", "Nougat", color.RGBA{ 0xd0, 0x91, 0x68, 255 } },
+		{ "312", "Medium Nougat", color.RGBA{ 0xaa, 0x7d, 0x55, 255 } },
+		{ "192", "Reddish Brown", color.RGBA{ 0x58, 0x2a, 0x12, 255 } },
+		{ "308", "Dark Brown", color.RGBA{ 0x35, 0x21, 0x00, 255 } },
+		{ "119", "Lime", color.RGBA{ 0xbb, 0xe9, 0x0b, 255 } },
+		{ "37", "Bright Green", color.RGBA{ 0x4b, 0x9f, 0x4a, 255 } },
+		{ "28", "Green", color.RGBA{ 0x23, 0x78, 0x41, 255 } },
+		{ "141", "Dark Green", color.RGBA{ 0x18, 0x46, 0x32, 255 } },
+		{ "330", "Olive Green", color.RGBA{ 0x9b, 0x9a, 0x5a, 255 } },
+		{ "151", "Sand Green", color.RGBA{ 0xa0, 0xbc, 0xac, 255 } },
+		{ "107", "Dark Turquoise", color.RGBA{ 0x00, 0x8f, 0x9b, 255 } },
+		{ "322", "Medium Azure", color.RGBA{ 0x36, 0xae, 0xbf, 255 } },
+		{ "321", "Dark Azure", color.RGBA{ 0x07, 0x8b, 0xc9, 255 } },
+		{ "212", "Bright Light Blue", color.RGBA{ 0x9f, 0xc3, 0xe9, 255 } },
+		{ "102", "Medium Blue", color.RGBA{ 0x5a, 0x93, 0xdb, 255 } },
+		{ "23", "Blue", color.RGBA{ 0x00, 0x55, 0xbf, 255 } },
+		{ "135", "Sand Blue", color.RGBA{ 0x60, 0x74, 0xa1, 255 } },
+		{ "140", "Dark Blue", color.RGBA{ 0x0a, 0x34, 0x63, 255 } },
+		{ "325", "Lavender", color.RGBA{ 0xe1, 0xd5, 0xed, 255 } },
+		{ "324", "Medium Lavender", color.RGBA{ 0xac, 0x78, 0xba, 255 } },
+		{ "268", "Dark Purple", color.RGBA{ 0x3f, 0x36, 0x91, 255 } },
+	},
+}
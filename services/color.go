@@ -0,0 +1,141 @@
+package services
+
+import (
+	"image/color"
+	"math"
+)
+
+// Lab is CIE L*a*b* color under D65 white point
+type Lab struct {
+	L, A, B float64
+}
+
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c + 0.055) / 1.055, 2.4)
+}
+
+func ToLab(c color.Color) Lab {
+	r8, g8, b8, _ := c.RGBA()
+	r, g, b := srgbToLinear(uint8(r8 >> 8)), srgbToLinear(uint8(g8 >> 8)), srgbToLinear(uint8(b8 >> 8))
+
+	x := (0.4124564 * r + 0.3575761 * g + 0.1804375 * b) / 0.95047
+	y := 0.2126729 * r + 0.7151522 * g + 0.0721750 * b
+	z := (0.0193339 * r + 0.1191920 * g + 0.9503041 * b) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0 / 24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0 / 27 * t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return Lab{ 116 * fy - 16, 500 * (fx - fy), 200 * (fy - fz) }
+}
+
+// ToOKLab converts to OKLab, coordinates share Lab struct (L in 0..1)
+func ToOKLab(c color.Color) Lab {
+	r8, g8, b8, _ := c.RGBA()
+	r, g, b := srgbToLinear(uint8(r8 >> 8)), srgbToLinear(uint8(g8 >> 8)), srgbToLinear(uint8(b8 >> 8))
+
+	l := math.Cbrt(0.4122214708 * r + 0.5363325363 * g + 0.0514459929 * b)
+	m := math.Cbrt(0.2119034982 * r + 0.6806995451 * g + 0.1073969566 * b)
+	s := math.Cbrt(0.0883024619 * r + 0.2817188376 * g + 0.6299787005 * b)
+	return Lab{
+		0.2104542553 * l + 0.7936177850 * m - 0.0040720468 * s,
+		1.9779984951 * l - 2.4285922050 * m + 0.4505937099 * s,
+		0.0259040371 * l + 0.7827717662 * m - 0.8086757660 * s,
+	}
+}
+
+// CIEDE2000 is perceptual color difference of two Lab colors, ~1 is barely visible
+func CIEDE2000(c1, c2 Lab) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	deg := func(r float64) float64 {
+		d := r * 180 / math.Pi
+		if d < 0 {
+			d += 360
+		}
+		return d
+	}
+	pow7 := func(v float64) float64 { return v * v * v * v * v * v * v }
+
+	cBar := (math.Hypot(c1.A, c1.B) + math.Hypot(c2.A, c2.B)) / 2
+	g := 0.5 * (1 - math.Sqrt(pow7(cBar) / (pow7(cBar) + pow7(25))))
+	a1, a2 := (1 + g) * c1.A, (1 + g) * c2.A
+	cp1, cp2 := math.Hypot(a1, c1.B), math.Hypot(a2, c2.B)
+	hp1, hp2 := 0.0, 0.0
+	if cp1 != 0 {
+		hp1 = deg(math.Atan2(c1.B, a1))
+	}
+	if cp2 != 0 {
+		hp2 = deg(math.Atan2(c2.B, a2))
+	}
+
+	dL, dC := c2.L - c1.L, cp2 - cp1
+	dh := 0.0
+	if cp1 * cp2 != 0 {
+		dh = hp2 - hp1
+		if dh > 180 {
+			dh -= 360
+		} else if dh < -180 {
+			dh += 360
+		}
+	}
+	dH := 2 * math.Sqrt(cp1 * cp2) * math.Sin(rad(dh / 2))
+
+	lBar, cpBar := (c1.L + c2.L) / 2, (cp1 + cp2) / 2
+	hBar := hp1 + hp2
+	if cp1 * cp2 != 0 {
+		switch {
+		case math.Abs(hp1 - hp2) <= 180:
+			hBar = (hp1 + hp2) / 2
+		case hp1 + hp2 < 360:
+			hBar = (hp1 + hp2 + 360) / 2
+		default:
+			hBar = (hp1 + hp2 - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17 * math.Cos(rad(hBar - 30)) + 0.24 * math.Cos(rad(2 * hBar)) +
+		0.32 * math.Cos(rad(3 * hBar + 6)) - 0.20 * math.Cos(rad(4 * hBar - 63))
+	dTheta := 30 * math.Exp(-math.Pow((hBar - 275) / 25, 2))
+	rc := 2 * math.Sqrt(pow7(cpBar) / (pow7(cpBar) + pow7(25)))
+	sl := 1 + 0.015 * math.Pow(lBar - 50, 2) / math.Sqrt(20 + math.Pow(lBar - 50, 2))
+	sc := 1 + 0.045 * cpBar
+	sh := 1 + 0.015 * cpBar * t
+	rt := -math.Sin(rad(2 * dTheta)) * rc
+
+	return math.Sqrt(math.Pow(dL / sl, 2) + math.Pow(dC / sc, 2) + math.Pow(dH / sh, 2) + rt * (dC / sc) * (dH / sh))
+}
+
+func linearToSrgb(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055 * math.Pow(c, 1 / 2.4) - 0.055
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 255))
+}
+
+// FromLab converts back to sRGB, out of gamut colors are clipped
+func FromLab(lab Lab) color.RGBA {
+	finv := func(t float64) float64 {
+		if t * t * t > 216.0 / 24389 {
+			return t * t * t
+		}
+		return (116 * t - 16) / (24389.0 / 27)
+	}
+	fy := (lab.L + 16) / 116
+	x, y, z := finv(fy + lab.A / 500) * 0.95047, finv(fy), finv(fy - lab.B / 200) * 1.08883
+
+	return color.RGBA{
+		linearToSrgb(3.2404542 * x - 1.5371385 * y - 0.4985314 * z),
+		linearToSrgb(-0.9692660 * x + 1.8760108 * y + 0.0415560 * z),
+		linearToSrgb(0.0556434 * x - 0.2040259 * y + 1.0572252 * z),
+		255,
+	}
+}
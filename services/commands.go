@@ -0,0 +1,188 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type fileResult struct {
+	index  int
+	path   string
+	output string
+	err    error
+}
+
+// processEach runs fn for every input file concurrently
+// and prints outputs to w in order of inputs, as soon as all earlier ones are done
+func processEach(config cmd.Config, w io.Writer, fn func(path string) (string, error)) Summary {
+	paths := config.InputFiles
+	summary := Summary{ Total: len(paths), Errors: make([]error, 0) }
+	ch := make(chan fileResult, len(paths))
+	progress := NewProgress(config.Verbosity)
+	progress.Start(len(paths))
+
+	workers := newWorkers(config.Workers)
+	for i, path := range paths {
+		go workers.run(func() {
+			out, err := fn(path)
+			ch <- fileResult{ i, path, out, err }
+		})
+	}
+
+	label := strings.ToUpper(string(config.Command))
+	outputs := make([]string, len(paths))
+	done := make([]bool, len(paths))
+	next := 0
+	for range paths {
+		res := <-ch
+		progress.Step(label, res.path, nil, res.err)
+		if res.err != nil {
+			summary.add(res.err)
+		} else {
+			outputs[res.index] = res.output
+		}
+		done[res.index] = true
+		for ; next < len(paths) && done[next]; next++ {
+			fmt.Fprint(w, outputs[next])
+		}
+	}
+	progress.Finish()
+
+	return summary
+}
+
+func readTiles(path string, config cmd.Config) (image.Image, string, []Tile, error) {
+	start := time.Now()
+	img, format, err := ReadImage(path)
+	if err != nil {
+		return nil, "", nil, &ProcessError{ path, string(config.Command), DECODE, err }
+	}
+	logDecoded(path, format, img, millis(time.Since(start)))
+	img = PrepareImage(img, config)
+	bounds := img.Bounds()
+	tiles := ImageTiles(bounds.Max.X, bounds.Max.Y, config)
+	return img, format, tiles, nil
+}
+
+func ExtractPalette(img image.Image, tiles []Tile) []color.RGBA {
+	colors := make([]color.RGBA, len(tiles))
+	for i, tile := range tiles {
+		colors[i] = AverageColor(img, tile)
+	}
+	return colors
+}
+
+func ExtractFiles(config cmd.Config) Summary {
+	return processEach(config, os.Stdout, func(path string) (string, error) {
+		img, _, tiles, err := readTiles(path, config)
+		if err != nil {
+			return "", err
+		}
+		colors := ExtractPalette(img, tiles)
+		rows, cols := gridShape(tiles)
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%s [%dx%d]\n", path, rows, cols))
+		for i, c := range colors {
+			sb.WriteString(HexColor(c))
+			if (i + 1) % cols == 0 {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(" ")
+			}
+		}
+		return sb.String(), nil
+	})
+}
+
+func ExportFiles(config cmd.Config) Summary {
+	if err := ensureOutputDir(config); err != nil {
+		return Summary{ Total: len(config.InputFiles), Failed: len(config.InputFiles), Errors: []error{ err } }
+	}
+	collisions := outputCollisions(config.InputFiles, func(path string) []string {
+		outs := make([]string, len(config.ExportFormats))
+		for i, format := range config.ExportFormats {
+			outs[i] = makeSwatchPath(config, path, format)
+		}
+		return outs
+	})
+	return processEach(config, os.Stdout, func(path string) (string, error) {
+		if err, ok := collisions[path]; ok {
+			return "", &ProcessError{ path, string(config.Command), ENCODE, err }
+		}
+		img, _, tiles, err := readTiles(path, config)
+		if err != nil {
+			return "", err
+		}
+		rows, cols := gridShape(tiles)
+		swatch := Swatch{
+			Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+			Rows: rows,
+			Cols: cols,
+			Colors: ExtractPalette(img, tiles),
+		}
+
+		for _, format := range config.ExportFormats {
+			out := makeSwatchPath(config, path, format)
+			if err := SaveSwatch(swatch, format, out); err != nil {
+				return "", &ProcessError{ path, format, ENCODE, err }
+			}
+		}
+		return "", nil
+	})
+}
+
+func InspectFiles(config cmd.Config) Summary {
+	return processEach(config, os.Stdout, func(path string) (string, error) {
+		img, format, tiles, err := readTiles(path, config)
+		if err != nil {
+			return "", err
+		}
+		bounds := img.Bounds()
+		if format == "" {
+			format = strings.TrimPrefix(filepath.Ext(path), ".")
+		}
+
+		minW, minH, maxW, maxH := bounds.Max.X, bounds.Max.Y, 0, 0
+		for _, t := range tiles {
+			w, h := t.XEnd - t.XStart, t.YEnd - t.YStart
+			minW, maxW = min(minW, w), max(maxW, w)
+			minH, maxH = min(minH, h), max(maxH, h)
+		}
+
+		colors := ExtractPalette(img, tiles)
+		var minL, maxL, sumL float64 = 255, 0, 0
+		for _, c := range colors {
+			l := luminance(c)
+			minL, maxL = min(minL, l), max(maxL, l)
+			sumL += l
+		}
+		whole := AverageColor(img, Tile{ 0, 0, bounds.Max.Y, bounds.Max.X })
+
+		var sb strings.Builder
+		sb.WriteString(path + "\n")
+		sb.WriteString(fmt.Sprintf("  format:        %s\n", format))
+		sb.WriteString(fmt.Sprintf("  size:          %dx%d\n", bounds.Dx(), bounds.Dy()))
+		sb.WriteString(fmt.Sprintf("  color model:   %s\n", colorModelName(img)))
+		rows, cols := gridShape(tiles)
+		sb.WriteString(fmt.Sprintf("  grid:          %dx%d (%d tiles)\n", rows, cols, len(tiles)))
+		sb.WriteString(fmt.Sprintf("  tile size:     %dx%d .. %dx%d\n", minW, minH, maxW, maxH))
+		sb.WriteString(fmt.Sprintf("  average color: %s\n", HexColor(whole)))
+		if len(colors) > 0 {
+			sb.WriteString(fmt.Sprintf("  tile luma:     min %.0f, max %.0f, mean %.0f\n", minL, maxL, sumL / float64(len(colors))))
+		}
+		return sb.String(), nil
+	})
+}
+
+// perceived brightness in 0..255 (Rec. 601)
+func luminance(c color.RGBA) float64 {
+	return 0.299 * float64(c.R) + 0.587 * float64(c.G) + 0.114 * float64(c.B)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"bytes"
+	"color-pallete/cmd"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessEach_InputOrder(t *testing.T) {
+	paths := make([]string, 8)
+	for i := range paths {
+		paths[i] = strconv.Itoa(i)
+	}
+	config := cmd.Config{ InputFiles: paths, Workers: len(paths), Verbosity: cmd.QUIET }
+	var out bytes.Buffer
+
+	summary := processEach(config, &out, func(path string) (string, error) {
+		// earlier inputs finish last
+		i, _ := strconv.Atoi(path)
+		time.Sleep(time.Duration(len(paths) - i) * 5 * time.Millisecond)
+		return path + "\n", nil
+	})
+
+	assert.Equal(t, 0, summary.Failed)
+	assert.Equal(t, "0\n1\n2\n3\n4\n5\n6\n7\n", out.String())
+}
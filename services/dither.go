@@ -0,0 +1,165 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// pixels sampled for palette extraction, bigger images are sampled with a step
+const QUANTIZE_SAMPLES = 1 << 16
+
+// QuantizePalette picks up to n representative colors with a median cut variant:
+// the box with the widest channel range is split at the channel mean until there are n boxes
+func QuantizePalette(img image.Image, n int) []color.RGBA {
+	bounds := img.Bounds()
+	step := max(1, int(math.Sqrt(float64(bounds.Dx() * bounds.Dy()) / QUANTIZE_SAMPLES)))
+	pixels := make([][3]uint8, 0, QUANTIZE_SAMPLES)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, _ := img.At(x, y).RGBA()
+			pixels = append(pixels, [3]uint8{ uint8(r >> 8), uint8(g >> 8), uint8(b >> 8) })
+		}
+	}
+	if len(pixels) == 0 || n < 1 {
+		return nil
+	}
+
+	boxes := [][][3]uint8{ pixels }
+	for len(boxes) < n {
+		widest, channel, widestRange := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 { continue }
+			c, r := widestChannel(box)
+			if r > widestRange {
+				widest, channel, widestRange = i, c, r
+			}
+		}
+		// every box holds a single color, there is nothing left to split
+		if widest < 0 { break }
+
+		box := boxes[widest]
+		sort.Slice(box, func(a, b int) bool { return box[a][channel] < box[b][channel] })
+		// split at channel mean, median would cut a big flat cluster in half
+		// and mix a small distinct one into the average of its lower half
+		sum := 0
+		for _, p := range box {
+			sum += int(p[channel])
+		}
+		mid := sort.Search(len(box), func(i int) bool { return int(box[i][channel]) * len(box) > sum })
+		mid = min(max(mid, 1), len(box) - 1)
+		boxes[widest] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	palette := make([]color.RGBA, len(boxes))
+	for i, box := range boxes {
+		var sum [3]int
+		for _, p := range box {
+			sum[0], sum[1], sum[2] = sum[0] + int(p[0]), sum[1] + int(p[1]), sum[2] + int(p[2])
+		}
+		palette[i] = color.RGBA{ uint8(sum[0] / len(box)), uint8(sum[1] / len(box)), uint8(sum[2] / len(box)), 255 }
+	}
+	return palette
+}
+
+func widestChannel(box [][3]uint8) (channel, spread int) {
+	lo, hi := [3]uint8{ 255, 255, 255 }, [3]uint8{}
+	for _, p := range box {
+		for c := 0; c < 3; c++ {
+			lo[c], hi[c] = min(lo[c], p[c]), max(hi[c], p[c])
+		}
+	}
+	for c := 0; c < 3; c++ {
+		if int(hi[c]) - int(lo[c]) > spread {
+			channel, spread = c, int(hi[c]) - int(lo[c])
+		}
+	}
+	return channel, spread
+}
+
+func nearestColor(palette []color.RGBA, r, g, b float64) color.RGBA {
+	best, bestDistance := palette[0], math.Inf(1)
+	for _, p := range palette {
+		dr, dg, db := r - float64(p.R), g - float64(p.G), b - float64(p.B)
+		if d := dr * dr + dg * dg + db * db; d < bestDistance {
+			best, bestDistance = p, d
+		}
+	}
+	return best
+}
+
+func clamp255(v float64) float64 {
+	return math.Max(0, math.Min(255, v))
+}
+
+type diffusion struct {
+	dx, dy int
+	weight float64
+}
+
+var DIFFUSION_KERNELS = map[cmd.DitherMethod][]diffusion{
+	cmd.FLOYD_STEINBERG: { { 1, 0, 7.0 / 16 }, { -1, 1, 3.0 / 16 }, { 0, 1, 5.0 / 16 }, { 1, 1, 1.0 / 16 } },
+	// spreads only 3/4 of the error, keeps contrast higher
+	cmd.ATKINSON: { { 1, 0, 1.0 / 8 }, { 2, 0, 1.0 / 8 }, { -1, 1, 1.0 / 8 }, { 0, 1, 1.0 / 8 }, { 1, 1, 1.0 / 8 }, { 0, 2, 1.0 / 8 } },
+}
+
+var BAYER_8X8 = [8][8]float64{
+	{ 0, 32, 8, 40, 2, 34, 10, 42 },
+	{ 48, 16, 56, 24, 50, 18, 58, 26 },
+	{ 12, 44, 4, 36, 14, 46, 6, 38 },
+	{ 60, 28, 52, 20, 62, 30, 54, 22 },
+	{ 3, 35, 11, 43, 1, 33, 9, 41 },
+	{ 51, 19, 59, 27, 49, 17, 57, 25 },
+	{ 15, 47, 7, 39, 13, 45, 5, 37 },
+	{ 63, 31, 55, 23, 61, 29, 53, 21 },
+}
+
+// Dither remaps src to palette pixel by pixel using error diffusion or ordered threshold map
+func Dither(src image.Image, palette []color.RGBA, method cmd.DitherMethod, dst *image.RGBA) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	errs := make([][3]float64, width * height)
+	kernel := DIFFUSION_KERNELS[method]
+	// ordered dithering offset range, roughly distance between neighbouring palette colors
+	spread := 255 / math.Max(math.Cbrt(float64(len(palette))), 1)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r8, g8, b8, _ := src.At(bounds.Min.X + x, bounds.Min.Y + y).RGBA()
+			e := errs[y * width + x]
+			r, g, b := float64(r8 >> 8) + e[0], float64(g8 >> 8) + e[1], float64(b8 >> 8) + e[2]
+			if method == cmd.BAYER {
+				offset := (BAYER_8X8[y % 8][x % 8] / 64 - 0.5) * spread
+				r, g, b = r + offset, g + offset, b + offset
+			}
+			// clamped so error collected in saturated areas doesn't bleed far away
+			r, g, b = clamp255(r), clamp255(g), clamp255(b)
+
+			c := nearestColor(palette, r, g, b)
+			dst.SetRGBA(x, y, c)
+
+			for _, k := range kernel {
+				nx, ny := x + k.dx, y + k.dy
+				if nx < 0 || nx >= width || ny >= height { continue }
+				n := &errs[ny * width + nx]
+				n[0] += (r - float64(c.R)) * k.weight
+				n[1] += (g - float64(c.G)) * k.weight
+				n[2] += (b - float64(c.B)) * k.weight
+			}
+		}
+	}
+}
+
+// DitherPainter dithers source with palette, see ModePalette
+func DitherPainter(palette []color.RGBA, method cmd.DitherMethod) PaintFunc {
+	return func(src image.Image, _ []Tile, dst *image.RGBA, _ []Tile) image.Image {
+		if len(palette) == 0 {
+			return dst
+		}
+		Dither(src, palette, method, dst)
+		return dst
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var gray = color.RGBA{ 128, 128, 128, 255 }
+
+func TestQuantizePalette_SplitImage(t *testing.T) {
+	palette := QuantizePalette(splitImage(20, 10, 10), 2)
+
+	assert.ElementsMatch(t, []color.RGBA{ white, black }, palette)
+}
+
+func TestQuantizePalette_UnevenClusters(t *testing.T) {
+	// a median split would put the black row and 4 gray rows into one box averaging to dark gray
+	img := uniformImage(10, 10, gray)
+	for x := 0; x < 10; x++ {
+		img.Set(x, 0, black)
+	}
+
+	palette := QuantizePalette(img, 2)
+
+	assert.ElementsMatch(t, []color.RGBA{ black, gray }, palette)
+}
+
+func TestQuantizePalette_FewerColorsThanRequested(t *testing.T) {
+	palette := QuantizePalette(uniformImage(10, 10, gray), 8)
+
+	assert.Equal(t, []color.RGBA{ gray }, palette)
+}
+
+func TestDither_MixesPaletteColors(t *testing.T) {
+	palette := []color.RGBA{ black, white }
+	for _, method := range []cmd.DitherMethod{ cmd.FLOYD_STEINBERG, cmd.ATKINSON, cmd.BAYER } {
+		dst := image.NewRGBA(image.Rect(0, 0, 16, 16))
+
+		Dither(uniformImage(16, 16, gray), palette, method, dst)
+
+		whites := 0
+		for i := 0; i < len(dst.Pix); i += 4 {
+			if dst.Pix[i] == 255 { whites++ }
+		}
+		// mid gray turns into roughly half white, half black pixels
+		assert.InDelta(t, 128, whites, 32, string(method))
+	}
+}
+
+func TestDither_NoneMapsToNearest(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	Dither(uniformImage(4, 4, gray), []color.RGBA{ black, white }, cmd.NO_DITHER, dst)
+
+	assert.Equal(t, white, dst.RGBAAt(0, 0))
+	assert.Equal(t, white, dst.RGBAAt(3, 3))
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Stage string
+const (
+	DECODE Stage = "decode"
+	PAINT  Stage = "paint"
+	ENCODE Stage = "encode"
+)
+
+// ProcessError is a failure of single (file, mode) pair
+type ProcessError struct {
+	Path  string
+	Mode  string // render mode, export format or command name
+	Stage Stage
+	Err   error
+}
+
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("%s [ %s ] %s failed: %s", e.Path, e.Mode, e.Stage, e.Err.Error())
+}
+
+func (e *ProcessError) Unwrap() error {
+	return e.Err
+}
+
+// Summary is the outcome of a whole run
+type Summary struct {
+	Total  int
+	Failed int
+	Errors []error
+}
+
+func (s *Summary) add(err error) {
+	s.Errors = append(s.Errors, err)
+	var pe *ProcessError
+	if errors.As(err, &pe) {
+		s.Failed++
+	}
+}
+
+func (s Summary) Done() int {
+	return s.Total - s.Failed
+}
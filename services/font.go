@@ -0,0 +1,101 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// tiny 3x5 bitmap font for labels & legends, one row per byte, 3 lowest bits are pixels
+const (
+	GLYPH_W = 3
+	GLYPH_H = 5
+)
+
+var glyphs = map[rune][GLYPH_H]uint8 {
+	'A': { 0b010, 0b101, 0b111, 0b101, 0b101 },
+	'B': { 0b110, 0b101, 0b110, 0b101, 0b110 },
+	'C': { 0b011, 0b100, 0b100, 0b100, 0b011 },
+	'D': { 0b110, 0b101, 0b101, 0b101, 0b110 },
+	'E': { 0b111, 0b100, 0b110, 0b100, 0b111 },
+	'F': { 0b111, 0b100, 0b110, 0b100, 0b100 },
+	'G': { 0b011, 0b100, 0b101, 0b101, 0b011 },
+	'H': { 0b101, 0b101, 0b111, 0b101, 0b101 },
+	'I': { 0b111, 0b010, 0b010, 0b010, 0b111 },
+	'J': { 0b001, 0b001, 0b001, 0b101, 0b010 },
+	'K': { 0b101, 0b101, 0b110, 0b101, 0b101 },
+	'L': { 0b100, 0b100, 0b100, 0b100, 0b111 },
+	'M': { 0b101, 0b111, 0b111, 0b101, 0b101 },
+	'N': { 0b110, 0b101, 0b101, 0b101, 0b101 },
+	'O': { 0b010, 0b101, 0b101, 0b101, 0b010 },
+	'P': { 0b110, 0b101, 0b110, 0b100, 0b100 },
+	'Q': { 0b010, 0b101, 0b101, 0b110, 0b011 },
+	'R': { 0b110, 0b101, 0b110, 0b101, 0b101 },
+	'S': { 0b011, 0b100, 0b010, 0b001, 0b110 },
+	'T': { 0b111, 0b010, 0b010, 0b010, 0b010 },
+	'U': { 0b101, 0b101, 0b101, 0b101, 0b111 },
+	'V': { 0b101, 0b101, 0b101, 0b101, 0b010 },
+	'W': { 0b101, 0b101, 0b111, 0b111, 0b101 },
+	'X': { 0b101, 0b101, 0b010, 0b101, 0b101 },
+	'Y': { 0b101, 0b101, 0b010, 0b010, 0b010 },
+	'Z': { 0b111, 0b001, 0b010, 0b100, 0b111 },
+	'0': { 0b111, 0b101, 0b101, 0b101, 0b111 },
+	'1': { 0b010, 0b110, 0b010, 0b010, 0b111 },
+	'2': { 0b110, 0b001, 0b010, 0b100, 0b111 },
+	'3': { 0b110, 0b001, 0b010, 0b001, 0b110 },
+	'4': { 0b101, 0b101, 0b111, 0b001, 0b001 },
+	'5': { 0b111, 0b100, 0b110, 0b001, 0b110 },
+	'6': { 0b011, 0b100, 0b110, 0b101, 0b010 },
+	'7': { 0b111, 0b001, 0b010, 0b010, 0b010 },
+	'8': { 0b010, 0b101, 0b010, 0b101, 0b010 },
+	'9': { 0b010, 0b101, 0b011, 0b001, 0b110 },
+	' ': { 0b000, 0b000, 0b000, 0b000, 0b000 },
+	'-': { 0b000, 0b000, 0b111, 0b000, 0b000 },
+	'+': { 0b000, 0b010, 0b111, 0b010, 0b000 },
+	'=': { 0b000, 0b111, 0b000, 0b111, 0b000 },
+	':': { 0b000, 0b010, 0b000, 0b010, 0b000 },
+	'.': { 0b000, 0b000, 0b000, 0b000, 0b010 },
+	'#': { 0b101, 0b111, 0b101, 0b111, 0b101 },
+	'/': { 0b001, 0b001, 0b010, 0b100, 0b100 },
+	'%': { 0b101, 0b001, 0b010, 0b100, 0b101 },
+	'(': { 0b010, 0b100, 0b100, 0b100, 0b010 },
+	')': { 0b010, 0b001, 0b001, 0b001, 0b010 },
+}
+
+// textWidth includes 1px (scaled) gap between glyphs
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n * (GLYPH_W + 1) - 1) * scale
+}
+
+func textHeight(scale int) int {
+	return GLYPH_H * scale
+}
+
+// drawText paints s with top-left corner at (x, y), unknown characters are skipped as blanks
+func drawText(dst *image.RGBA, x, y int, s string, scale int, c color.RGBA) {
+	for _, ch := range strings.ToUpper(s) {
+		glyph := glyphs[ch]
+		for row := 0; row < GLYPH_H; row++ {
+			for col := 0; col < GLYPH_W; col++ {
+				if glyph[row] & (1 << (GLYPH_W - 1 - col)) == 0 {
+					continue
+				}
+				fillRect(dst, image.Rect(x + col * scale, y + row * scale, x + (col + 1) * scale, y + (row + 1) * scale), c)
+			}
+		}
+		x += (GLYPH_W + 1) * scale
+	}
+}
+
+func fillRect(dst *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(dst.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dst.SetRGBA(x, y, c)
+		}
+	}
+}
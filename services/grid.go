@@ -0,0 +1,140 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+)
+
+// GridPainter draws grid lines with given style over a copy of source image
+func GridPainter(style cmd.GridStyle) PaintFunc {
+	if style == (cmd.GridStyle{}) {
+		style = cmd.DEFAULT_GRID_STYLE
+	}
+	return func(src image.Image, tiles []Tile, dst *image.RGBA, _ []Tile) image.Image {
+		DrawStyledGrid(src, tiles, dst, style)
+		if style.CellLabels {
+			drawCellLabels(src, dst, tiles)
+		}
+		if style.Labels {
+			return addLabelMargin(dst, tiles)
+		}
+		return dst
+	}
+}
+
+func DrawStyledGrid(src image.Image, tiles []Tile, dst *image.RGBA, style cmd.GridStyle) image.Image {
+	bounds := src.Bounds()
+	lineColor, err := cmd.ParseHexColor(style.Color)
+	if err != nil {
+		lineColor = color.RGBA{ 0, 0, 0, 255 }
+	}
+	auto := style.Color == cmd.AUTO_COLOR
+	width := max(style.Width, 1)
+	// line is centered on the tile edge, 1px line stays inside the tile as before
+	before, after := (width + 1) / 2, width / 2
+
+	// copy non-grid colors, including pixels left outside of tiles
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dst.Set(x, y, src.At(x, y))
+		}
+	}
+
+	for _, tile := range tiles {
+		c := lineColor
+		if auto {
+			c = contrastColor(AverageColor(src, tile))
+		}
+
+		// paint vertical lines
+		if tile.XEnd != bounds.Max.X {
+			for x := tile.XEnd - before; x < tile.XEnd + after; x++ {
+				for y := tile.YStart; y < tile.YEnd; y++ {
+					paintLinePixel(src, dst, x, y, y, c, style)
+				}
+			}
+		}
+
+		// paint horizontal lines
+		if tile.YEnd != bounds.Max.Y {
+			for y := tile.YEnd - before; y < tile.YEnd + after; y++ {
+				for x := tile.XStart; x < tile.XEnd; x++ {
+					paintLinePixel(src, dst, x, y, x, c, style)
+				}
+			}
+		}
+	}
+
+	// whole image lines (outline, border) contrast with image average in auto mode
+	outlineColor := lineColor
+	if auto {
+		outlineColor = contrastColor(AverageColor(src, Tile{ 0, 0, bounds.Max.Y, bounds.Max.X }))
+	}
+
+	// close left & top sides of a grid which doesn't start at image edge
+	if len(tiles) > 0 && (tiles[0].XStart > 0 || tiles[0].YStart > 0) {
+		first, last := tiles[0], tiles[len(tiles) - 1]
+		for i := 0; i < width; i++ {
+			if first.XStart > 0 {
+				for y := first.YStart; y < last.YEnd; y++ {
+					paintLinePixel(src, dst, first.XStart + i, y, y, outlineColor, style)
+				}
+			}
+			if first.YStart > 0 {
+				for x := first.XStart; x < last.XEnd; x++ {
+					paintLinePixel(src, dst, x, first.YStart + i, x, outlineColor, style)
+				}
+			}
+		}
+	}
+
+	if style.Border {
+		drawBorder(src, dst, outlineColor, style)
+	}
+
+	return dst
+}
+
+// drawBorder outlines image edges with lines of style width
+func drawBorder(src image.Image, dst *image.RGBA, c color.RGBA, style cmd.GridStyle) {
+	bounds := src.Bounds()
+	for i := 0; i < max(style.Width, 1); i++ {
+		for x := 0; x < bounds.Max.X; x++ {
+			paintLinePixel(src, dst, x, i, x, c, style)
+			paintLinePixel(src, dst, x, bounds.Max.Y - 1 - i, x, c, style)
+		}
+		for y := 0; y < bounds.Max.Y; y++ {
+			paintLinePixel(src, dst, i, y, y, c, style)
+			paintLinePixel(src, dst, bounds.Max.X - 1 - i, y, y, c, style)
+		}
+	}
+}
+
+// paintLinePixel blends line color over source pixel, pos is position along the line for dashes
+func paintLinePixel(src image.Image, dst *image.RGBA, x, y, pos int, c color.RGBA, style cmd.GridStyle) {
+	if !(image.Point{ x, y }).In(src.Bounds()) {
+		return
+	}
+	if style.Dash > 0 && (pos / style.Dash) % 2 == 1 {
+		return
+	}
+	alpha := style.Opacity * float64(c.A) / 255
+	dst.Set(x, y, blend(src.At(x, y), c, alpha))
+}
+
+func blend(under color.Color, over color.RGBA, alpha float64) color.RGBA {
+	r, g, b, _ := under.RGBA()
+	mix := func(u uint32, o uint8) uint8 {
+		return uint8(float64(u >> 8) * (1 - alpha) + float64(o) * alpha + 0.5)
+	}
+	return color.RGBA{ mix(r, over.R), mix(g, over.G), mix(b, over.B), 255 }
+}
+
+// contrastColor is black on light tiles and white on dark ones
+func contrastColor(c color.RGBA) color.RGBA {
+	if luminance(c) > 128 {
+		return color.RGBA{ 0, 0, 0, 255 }
+	}
+	return color.RGBA{ 255, 255, 255, 255 }
+}
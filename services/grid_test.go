@@ -0,0 +1,81 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var white = color.RGBA{ 255, 255, 255, 255 }
+var black = color.RGBA{ 0, 0, 0, 255 }
+
+func uniformImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func drawTestGrid(src *image.RGBA, rows, cols int, style cmd.GridStyle) *image.RGBA {
+	tiles := MakeTiles(src.Bounds().Dx(), src.Bounds().Dy(), rows, cols)
+	dst := image.NewRGBA(src.Bounds())
+	DrawStyledGrid(src, tiles, dst, style)
+	return dst
+}
+
+func TestDrawGrid_Default(t *testing.T) {
+	src := uniformImage(4, 4, white)
+	tiles := MakeTiles(4, 4, 2, 2)
+	dst := image.NewRGBA(src.Bounds())
+
+	DrawGrid(src, tiles, dst, tiles)
+
+	assert.Equal(t, black, dst.RGBAAt(1, 0))
+	assert.Equal(t, black, dst.RGBAAt(0, 1))
+	assert.Equal(t, white, dst.RGBAAt(2, 0))
+	assert.Equal(t, white, dst.RGBAAt(3, 3))
+}
+
+func TestDrawStyledGrid_WidthAndColor(t *testing.T) {
+	style := cmd.GridStyle{ Color: "#ff0000", Width: 2, Opacity: 1 }
+	red := color.RGBA{ 255, 0, 0, 255 }
+
+	dst := drawTestGrid(uniformImage(8, 8, white), 2, 2, style)
+
+	assert.Equal(t, red, dst.RGBAAt(3, 0))
+	assert.Equal(t, red, dst.RGBAAt(4, 0))
+	assert.Equal(t, white, dst.RGBAAt(2, 0))
+	assert.Equal(t, white, dst.RGBAAt(5, 0))
+}
+
+func TestDrawStyledGrid_OpacityDashBorder(t *testing.T) {
+	style := cmd.GridStyle{ Color: "#000000", Width: 1, Opacity: 0.5, Dash: 2, Border: true }
+	gray := color.RGBA{ 128, 128, 128, 255 }
+
+	dst := drawTestGrid(uniformImage(8, 8, white), 1, 2, style)
+
+	// vertical line at x = 3, dashes of 2 px
+	assert.Equal(t, gray, dst.RGBAAt(3, 0))
+	assert.Equal(t, gray, dst.RGBAAt(3, 1))
+	assert.Equal(t, white, dst.RGBAAt(3, 2))
+	assert.Equal(t, white, dst.RGBAAt(3, 3))
+	// border
+	assert.Equal(t, gray, dst.RGBAAt(7, 4))
+	assert.Equal(t, gray, dst.RGBAAt(4, 7))
+}
+
+func TestDrawStyledGrid_AutoContrast(t *testing.T) {
+	style := cmd.GridStyle{ Color: cmd.AUTO_COLOR, Width: 1, Opacity: 1 }
+
+	dark := drawTestGrid(uniformImage(4, 4, black), 2, 2, style)
+	light := drawTestGrid(uniformImage(4, 4, white), 2, 2, style)
+
+	assert.Equal(t, white, dark.RGBAAt(1, 0))
+	assert.Equal(t, black, light.RGBAAt(1, 0))
+}
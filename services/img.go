@@ -8,89 +8,266 @@ import (
 	"image/color"
 	_ "image/jpeg"
 	"image/png"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/nickalie/go-webpbin"
 	_ "github.com/nickalie/go-webpbin"
 )
 
+// file name used for outputs of "-" input
+const STDIN_NAME = "stdin.png"
+
 type PaintFunc func(image.Image, []Tile, *image.RGBA, []Tile) image.Image
 
 type GPResult struct {
 	path string
 	mode cmd.Mode
 	err error
+	output string
+	record Record
+}
+
+// ModeInputs are files modes share between all inputs, loaded once before processing
+type ModeInputs struct {
+	Palette   []color.RGBA // -k palette= colors
+	Reference TransferReference
+}
+
+// LoadModeInputs reads palette & reference files of config modes, failures are kept per mode
+func LoadModeInputs(config cmd.Config) (ModeInputs, map[cmd.Mode]*ProcessError) {
+	var inputs ModeInputs
+	failures := make(map[cmd.Mode]*ProcessError)
+	var paletteErr, referenceErr error
+	paletteLoaded, referenceLoaded := false, false
+	for _, m := range config.Modes {
+		mode := cmd.Mode(m)
+		switch mode {
+		case cmd.DITHER, cmd.POSTERIZE, cmd.PAINT_BY_NUMBER:
+			if config.ModeOptions.Palette == "" { continue }
+			if !paletteLoaded {
+				s, err := LoadSwatch(config.ModeOptions.Palette)
+				inputs.Palette, paletteErr, paletteLoaded = s.Colors, err, true
+			}
+			if paletteErr != nil {
+				failures[mode] = &ProcessError{ "", m, PAINT, paletteErr }
+			}
+		case cmd.TRANSFER:
+			if !referenceLoaded {
+				ref, _, err := ReadImage(config.ModeOptions.Reference)
+				if err == nil {
+					inputs.Reference = NewTransferReference(ref, config.ModeOptions)
+				} else {
+					referenceErr = errors.New("reference " + config.ModeOptions.Reference + ": " + err.Error())
+				}
+				referenceLoaded = true
+			}
+			if referenceErr != nil {
+				failures[mode] = &ProcessError{ "", m, DECODE, referenceErr }
+			}
+		}
+	}
+	return inputs, failures
 }
 
-func ProcessFiles(config cmd.Config) []error {
-	errs := make([]error, 0)
+func ProcessFiles(config cmd.Config) Summary {
 	filesCount := len(config.Modes) * len(config.InputFiles)
+	summary := Summary{ Total: filesCount, Errors: make([]error, 0) }
+	if err := ensureOutputDir(config); err != nil {
+		summary.Failed = filesCount
+		summary.Errors = append(summary.Errors, err)
+		return summary
+	}
 	imageProcessingCh := make(chan GPResult, filesCount)
+	progress := NewProgress(config.Verbosity)
+	progress.Start(filesCount)
 
-	for _, path := range config.InputFiles {
-		for _, m := range config.Modes {
-			go ProcessFileAsync(path, config, cmd.Mode(m), imageProcessingCh)
+	fail := func(path string, mode cmd.Mode, stage Stage, err error) {
+		record := Record{ Input: path, Mode: string(mode), Stage: string(stage), Error: err.Error() }
+		imageProcessingCh <- GPResult{ path, mode, &ProcessError{ path, string(mode), stage, err }, "", record }
+	}
+
+	inputs, failures := LoadModeInputs(config)
+	workers := newWorkers(config.Workers)
+	for _, m := range config.Modes {
+		mode := cmd.Mode(m)
+		collisions := outputCollisions(config.InputFiles, func(path string) []string {
+			return []string{ makeOutputPath(config, path, strings.ToLower(m)) }
+		})
+		for _, path := range config.InputFiles {
+			if failure, ok := failures[mode]; ok {
+				fail(path, mode, failure.Stage, failure.Err)
+				continue
+			}
+			if err, ok := collisions[path]; ok {
+				fail(path, mode, ENCODE, err)
+				continue
+			}
+			go workers.run(func() {
+				ProcessFileAsync(path, config, mode, inputs, imageProcessingCh)
+			})
 		}
 	}
 
+	outputs := make([]string, 0, filesCount)
+	records := make([]Record, 0, filesCount)
 	for i := 0; i < filesCount; i++ {
 		res := <-imageProcessingCh
+		records = append(records, res.record)
+		progress.Step(string(res.mode), res.path, &res.record, res.err)
 		if res.err != nil {
-			errs = append(errs, res.err)
+			summary.add(res.err)
 			continue
 		}
-		fmt.Printf("done: [ %s ] for %s (%d / %d)\n", string(res.mode), res.path, i + 1, filesCount)
+		if res.output != cmd.STDIO {
+			outputs = append(outputs, res.output)
+		}
+	}
+	progress.Finish()
+
+	if err := cmd.RecordOutputs(outputs); err != nil {
+		summary.add(err)
+	}
+	if err := WriteReport(config, records); err != nil {
+		summary.add(err)
 	}
 	
-	return errs
+	return summary
 }
 
-func ProcessFileAsync(path string, config cmd.Config, mode cmd.Mode, ch chan GPResult) {
-	img, _, err := ReadImage(path)
+func ProcessFileAsync(path string, config cmd.Config, mode cmd.Mode, inputs ModeInputs, ch chan GPResult) {
+	record := Record{ Input: path, Mode: string(mode) }
+	fail := func(stage Stage, err error) {
+		record.Stage, record.Error = string(stage), err.Error()
+		ch <- GPResult{ path, mode, &ProcessError{ path, string(mode), stage, err }, "", record }
+	}
+
+	start := time.Now()
+	img, format, err := ReadImage(path)
+	record.Timings.Decode = millis(time.Since(start))
 	if err != nil {
-		ch <- GPResult{ path, mode, err}
+		fail(DECODE, err)
 		return
 	}
+	logDecoded(path, format, img, record.Timings.Decode)
+
+	start = time.Now()
+	img = PrepareImage(img, config)
 	colors := GetColors(img)
-	inTiles := MakeTiles(len(colors[0]), len(colors), config.GridRows, config.GridCols)
+	inTiles := ImageTiles(len(colors[0]), len(colors), config)
+	record.GridRows, record.GridCols = gridShape(inTiles)
+	if mode == cmd.QUADTREE {
+		inTiles = QuadTiles(img, inTiles, config.ModeOptions.Depth, config.ModeOptions.Threshold)
+	}
 
 	dstBounds := img.Bounds()
 	outTiles := inTiles
-	shouldUseConfigBounds := (mode == cmd.PALLETE) && (config.OutputHeight > 0 && config.OutputWidth > 0)
+	shouldUseConfigBounds := (mode == cmd.PALLETE || mode == cmd.QUADTREE) && (config.OutputHeight > 0 && config.OutputWidth > 0)
 	if shouldUseConfigBounds {
 		dstBounds = image.Rectangle{
 			image.Point{ 0, 0 },
 			image.Point{ config.OutputWidth, config.OutputHeight },
 		}
-		outTiles = MakeTiles(config.OutputWidth, config.OutputHeight, config.GridRows, config.GridCols)
+		if mode == cmd.QUADTREE {
+			outTiles = ScaleTiles(inTiles, img.Bounds().Dx(), img.Bounds().Dy(), config.OutputWidth, config.OutputHeight)
+		} else {
+			outTiles = OutputTiles(inTiles, img.Bounds(), config.OutputWidth, config.OutputHeight, config)
+		}
+	}
+	switch mode {
+	case cmd.PIXELATE:
+		// integer scale keeps blocks sharp, no interpolation between tiles
+		scale := max(config.ModeOptions.Scale, 1)
+		w, h := img.Bounds().Dx(), img.Bounds().Dy()
+		dstBounds = image.Rect(0, 0, w * scale, h * scale)
+		outTiles = ScaleTiles(inTiles, w, h, w * scale, h * scale)
+	case cmd.SPRITE:
+		dstBounds = image.Rect(0, 0, record.GridCols, record.GridRows)
+		outTiles = SpriteTiles(record.GridRows, record.GridCols)
 	}
 	dst := image.NewRGBA(dstBounds)
 	
 	var Paint PaintFunc
+	// writes outputs besides the image, e.g. svg of paint-by-number sheet
+	var saveExtra func(outPath string) error
 	switch mode {
 	case cmd.GRID:
-		Paint = DrawGrid
-	case cmd.PALLETE:
+		Paint = GridPainter(config.GridStyle)
+	case cmd.PALLETE, cmd.QUADTREE, cmd.PIXELATE, cmd.SPRITE:
 		Paint = DrawPallete
+	case cmd.DITHER, cmd.POSTERIZE, cmd.PAINT_BY_NUMBER:
+		palette := ModePalette(img, inputs, config.ModeOptions)
+		switch mode {
+		case cmd.DITHER:
+			Paint = DitherPainter(palette, config.ModeOptions.Dither)
+		case cmd.POSTERIZE:
+			Paint = PosterizePainter(palette, config.ModeOptions.Metric)
+		default:
+			sheet := NewPaintByNumber(img, palette, config.ModeOptions.Metric, config.ModeOptions.MinRegion)
+			Paint = sheet.Painter()
+			saveExtra = func(outPath string) error {
+				return sheet.SaveSVG(makeExtraPath(outPath, ".svg"))
+			}
+		}
+	case cmd.CROSS_STITCH:
+		stitches := NewStitches(img, inTiles, DMC_FLOSS, config.ModeOptions.Colors, config.ModeOptions.Metric)
+		Paint = stitches.Painter()
+		saveExtra = func(outPath string) error {
+			return stitches.SaveLegend(makeExtraPath(outPath, ".csv"))
+		}
+	case cmd.MOSAIC:
+		mosaic := NewMosaic(img, inTiles, BRICK_COLORS, config.ModeOptions.Metric)
+		Paint = mosaic.Painter()
+		saveExtra = func(outPath string) error {
+			return mosaic.SaveBillOfMaterials(makeExtraPath(outPath, ".csv"))
+		}
+	case cmd.TRANSFER:
+		Paint = TransferPainter(inputs.Reference, config.ModeOptions)
 	default:
-		ch <- GPResult{ path, mode, errors.New("invalid paint mode, expected [GRID | PALLETE], got " + string(mode)) }
+		fail(PAINT, errors.New("invalid paint mode, expected [GRID | PALLETE | QUADTREE | PIXELATE | SPRITE | DITHER | POSTERIZE | TRANSFER | PAINT-BY-NUMBER | CROSS-STITCH | MOSAIC], got " + string(mode)))
 		return
 	}
+	// other modes need rect tiles, quadtree is a tiling of its own
+	if config.IsTessellated() && (mode == cmd.GRID || mode == cmd.PALLETE) {
+		regions := ImageRegions(img, config)
+		outRegions := OutputRegions(img, regions, dstBounds.Dx(), dstBounds.Dy(), config)
+		Paint = RegionPainter(mode, regions, outRegions, config.GridStyle)
+	}
 	output := Paint(img, inTiles, dst, outTiles)
+	record.Timings.Paint = millis(time.Since(start))
+	record.Width, record.Height = output.Bounds().Dx(), output.Bounds().Dy()
 
 	suffix := strings.ToLower(string(mode))
-	err = SaveImage(output, makePath(path, suffix))
+	outPath := makeOutputPath(config, path, suffix)
+	start = time.Now()
+	err = SaveImage(output, outPath)
+	if err == nil && saveExtra != nil {
+		if outPath == cmd.STDIO {
+			slog.Warn("extra outputs are not written to stdout", "path", path, "mode", mode)
+		} else {
+			err = saveExtra(outPath)
+		}
+	}
+	record.Timings.Encode = millis(time.Since(start))
 	if err != nil {
-		ch <- GPResult{ path, mode, err }
+		fail(ENCODE, err)
 		return
 	}
 
-	ch <- GPResult{ path, mode, nil}
+	record.Output = outPath
+	slog.Debug("image processed", "path", path, "mode", mode, "output", outPath,
+		"width", record.Width, "height", record.Height,
+		"paintMs", record.Timings.Paint, "encodeMs", record.Timings.Encode)
+	ch <- GPResult{ path, mode, nil, outPath, record }
 }
 
 func ReadImage(path string) (image.Image, string, error) {
+	if path == cmd.STDIO {
+		return decodeStdin()
+	}
 	srcFile, err := os.Open(path)
 	if err != nil {
 		return nil, "", err
@@ -112,6 +289,16 @@ func ReadImage(path string) (image.Image, string, error) {
 	return img, format, nil
 }
 
+func colorModelName(img image.Image) string {
+	return fmt.Sprintf("%T", img)
+}
+
+func logDecoded(path, format string, img image.Image, decodeMs float64) {
+	bounds := img.Bounds()
+	slog.Debug("image decoded", "path", path, "format", format,
+		"width", bounds.Dx(), "height", bounds.Dy(), "colorModel", colorModelName(img), "decodeMs", decodeMs)
+}
+
 func GetColors(img image.Image) [][]color.Color {
 	bounds := img.Bounds()
 	width, height := bounds.Max.X, bounds.Max.Y
@@ -167,6 +354,29 @@ func MakeTiles(width, height int, rows, cols int) []Tile {
 	return tiles
 }
 
+// MakeSizedTiles covers image with tileWidth x tileHeight tiles,
+// last column / row is a narrower remainder strip when size doesn't divide evenly
+func MakeSizedTiles(width, height int, tileWidth, tileHeight int) []Tile {
+	tileWidth, tileHeight = min(tileWidth, width), min(tileHeight, height)
+	rows, cols := SizedGrid(width, height, tileWidth, tileHeight)
+
+	tiles := make([]Tile, 0, rows * cols)
+	for h := 0; h < height; h += tileHeight {
+		for w := 0; w < width; w += tileWidth {
+			tiles = append(tiles, Tile{ h, w, min(h + tileHeight, height), min(w + tileWidth, width) })
+		}
+	}
+	return tiles
+}
+
+// SizedGrid is number of rows / cols, including remainder strips, for given tile size
+func SizedGrid(width, height int, tileWidth, tileHeight int) (int, int) {
+	if tileWidth < 1 || tileHeight < 1 {
+		return 0, 0
+	}
+	return (height + tileHeight - 1) / tileHeight, (width + tileWidth - 1) / tileWidth
+}
+
 func DrawPallete(src image.Image, inTiles []Tile, dst *image.RGBA, outTiles []Tile) image.Image {
 	for i := range inTiles {
 		DrawTile(src, inTiles[i], dst, outTiles[i])
@@ -176,60 +386,46 @@ func DrawPallete(src image.Image, inTiles []Tile, dst *image.RGBA, outTiles []Ti
 }
 
 func DrawTile(src image.Image, inTile Tile, dst *image.RGBA, outTile Tile) {
+	avgColor := AverageColor(src, inTile)
+
+	for y := outTile.YStart; y < outTile.YEnd; y++ {
+		for x := outTile.XStart; x < outTile.XEnd; x++ {
+			dst.Set(x, y, avgColor)
+		}
+	}
+}
+
+func AverageColor(src image.Image, tile Tile) color.RGBA {
 	var rTotal, gTotal, bTotal uint64
-	totalPixels := uint64((inTile.XEnd - inTile.XStart) * (inTile.YEnd - inTile.YStart))
-	for y := inTile.YStart; y < inTile.YEnd; y++ {
-		for x := inTile.XStart; x < inTile.XEnd; x++ {
+	totalPixels := uint64((tile.XEnd - tile.XStart) * (tile.YEnd - tile.YStart))
+	if totalPixels == 0 {
+		return color.RGBA{ 0, 0, 0, 255 }
+	}
+	for y := tile.YStart; y < tile.YEnd; y++ {
+		for x := tile.XStart; x < tile.XEnd; x++ {
 			r, g, b, _ := src.At(x, y).RGBA()
 			rTotal += uint64(r >> 8)
 			gTotal += uint64(g >> 8)
 			bTotal += uint64(b >> 8)
 		}
 	}
-	
-	avgColor := color.RGBA {
+
+	return color.RGBA {
 		R: uint8(rTotal / totalPixels),
 		G: uint8(gTotal / totalPixels),
 		B: uint8(bTotal / totalPixels),
 		A: 255,
 	}
-
-	for y := outTile.YStart; y < outTile.YEnd; y++ {
-		for x := outTile.XStart; x < outTile.XEnd; x++ {
-			dst.Set(x, y, avgColor)
-		}
-	}
 }
 
-func DrawGrid(src image.Image, tiles []Tile, dst *image.RGBA, _ []Tile) image.Image {
-	lineColor := color.RGBA { 0, 0, 0, 255 }
-	for _, tile := range tiles {
-		// copy non-grid colors
-		for y := tile.YStart; y < tile.YEnd; y++ {
-			for x := tile.XStart; x < tile.XEnd; x++ {
-				dst.Set(x, y, src.At(x, y))
-			}
-		}
-
-		// paint vertical lines
-		if tile.XEnd != src.Bounds().Max.X {
-			for y := tile.YStart; y < tile.YEnd; y++ {
-				dst.Set(tile.XEnd - 1, y, lineColor)
-			}
-		}
-
-		// paint horizontal lines
-		if tile.YEnd != src.Bounds().Max.Y {
-			for x := tile.XStart; x < tile.XEnd; x++ {
-				dst.Set(x, tile.YEnd - 1, lineColor)
-			}
-		}
-	}
-
-	return dst
+func DrawGrid(src image.Image, tiles []Tile, dst *image.RGBA, outTiles []Tile) image.Image {
+	return DrawStyledGrid(src, tiles, dst, cmd.DEFAULT_GRID_STYLE)
 }
 
 func SaveImage(img image.Image, path string) error {
+	if path == cmd.STDIO {
+		return png.Encode(os.Stdout, img)
+	}
 	outFile, err := os.Create(path)
 	if err != nil {
 		return err
@@ -249,6 +445,58 @@ func makePath(original, suffix string) string {
 	return name + "." + parts[len(parts) - 1]
 }
 
+// makeOutputPath moves output into config.OutputDir when it's set
+func makeOutputPath(config cmd.Config, original, suffix string) string {
+	if config.OutputDir == cmd.STDIO {
+		return cmd.STDIO
+	}
+	if original == cmd.STDIO {
+		original = STDIN_NAME
+	}
+	path := makePath(original, suffix)
+	if config.OutputDir == "" {
+		return path
+	}
+	return filepath.Join(config.OutputDir, filepath.Base(path))
+}
+
+// outputCollisions finds inputs whose output is already claimed by an earlier input,
+// e.g. same named files from different folders written into one -o folder.
+// Such inputs fail instead of overwriting each other concurrently
+func outputCollisions(paths []string, outputs func(path string) []string) map[string]error {
+	owners := make(map[string]string)
+	collisions := make(map[string]error)
+	for _, path := range paths {
+		for _, out := range outputs(path) {
+			if out == cmd.STDIO { continue }
+			key := cmd.NormalizePath(out)
+			owner, ok := owners[key]
+			if !ok {
+				owners[key] = path
+				continue
+			}
+			// the same input listed twice writes the same file, there is nothing to lose
+			if cmd.NormalizePath(owner) != cmd.NormalizePath(path) {
+				collisions[path] = errors.New("output " + out + " is already written for " + owner)
+				break
+			}
+		}
+	}
+	return collisions
+}
+
+// makeExtraPath swaps extension of an image output, "photo-paint-by-number.jpg" -> "photo-paint-by-number.svg"
+func makeExtraPath(outPath, ext string) string {
+	return strings.TrimSuffix(outPath, filepath.Ext(outPath)) + ext
+}
+
+func ensureOutputDir(config cmd.Config) error {
+	if config.OutputDir == "" || config.OutputDir == cmd.STDIO {
+		return nil
+	}
+	return os.MkdirAll(config.OutputDir, 0755)
+}
+
 func join(parts []string, ch string) string {
 	var sb strings.Builder
 	for _, part := range parts[:len(parts) - 1] {
@@ -1,6 +1,12 @@
 package services
 
 import (
+	"bytes"
+	"color-pallete/cmd"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,4 +65,192 @@ func TestMakePath_FileInsideFolder(t *testing.T) {
 	got := makePath("fold/filename.jpg", "grid")
 
 	assert.Equal(t, want, got)
-}
\ No newline at end of file
+}
+
+func TestMakeOutputPath_OutputDir(t *testing.T) {
+	config := cmd.Config{ OutputDir: "out" }
+
+	assert.Equal(t, "out/filename-grid.jpg", makeOutputPath(config, "fold/filename.jpg", "grid"))
+	assert.Equal(t, "fold/filename-grid.jpg", makeOutputPath(cmd.Config{}, "fold/filename.jpg", "grid"))
+}
+
+func TestMakeOutputPath_Stdio(t *testing.T) {
+	assert.Equal(t, "-", makeOutputPath(cmd.Config{ OutputDir: "-" }, "photo.jpg", "grid"))
+	assert.Equal(t, "out/stdin-grid.png", makeOutputPath(cmd.Config{ OutputDir: "out" }, "-", "grid"))
+}
+
+func TestIsWebP(t *testing.T) {
+	assert.True(t, isWebP([]byte("RIFF\x00\x00\x00\x00WEBPVP8 ")))
+	assert.False(t, isWebP([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x00")))
+}
+
+func TestProcessFiles_DecodeError(t *testing.T) {
+	assert := assert.New(t)
+	config := cmd.Config{ InputFiles: []string{ "missing.png" }, GridRows: 2, GridCols: 2, Modes: []string{ "GRID" }, Workers: 1 }
+
+	summary := ProcessFiles(config)
+
+	assert.Equal(1, summary.Total)
+	assert.Equal(1, summary.Failed)
+	var pe *ProcessError
+	assert.ErrorAs(summary.Errors[0], &pe)
+	assert.Equal(DECODE, pe.Stage)
+	assert.Equal("missing.png", pe.Path)
+	assert.Equal("GRID", pe.Mode)
+}
+
+func TestProcessFiles_OutputCollision(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "d1", "x.png"), filepath.Join(dir, "d2", "x.png")
+	for _, path := range []string{ first, second } {
+		assert.Nil(os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(SaveImage(uniformImage(4, 4, white), path))
+	}
+	out := filepath.Join(dir, "out")
+	config := cmd.Config{ InputFiles: []string{ first, second }, GridRows: 2, GridCols: 2, Modes: []string{ "GRID" }, OutputDir: out, Workers: 2, Verbosity: cmd.QUIET }
+
+	summary := ProcessFiles(config)
+
+	assert.Equal(2, summary.Total)
+	assert.Equal(1, summary.Failed)
+	var pe *ProcessError
+	assert.ErrorAs(summary.Errors[0], &pe)
+	assert.Equal(second, pe.Path)
+	assert.ErrorContains(pe, first)
+	_, err := os.Stat(filepath.Join(out, "x-grid.png"))
+	assert.Nil(err)
+}
+
+func TestOutputCollisions_SameInputTwice(t *testing.T) {
+	collisions := outputCollisions([]string{ "a/x.png", "./a/x.png", "b/x.png" }, func(path string) []string {
+		return []string{ filepath.Base(path) }
+	})
+
+	assert.Len(t, collisions, 1)
+	assert.ErrorContains(t, collisions["b/x.png"], "a/x.png")
+}
+
+func TestOutputCollisions_RelativeAndAbsoluteOutput(t *testing.T) {
+	abs, err := filepath.Abs(filepath.Join("out", "x.png"))
+	assert.Nil(t, err)
+	outputs := map[string]string{ "a/x.png": filepath.Join("out", "x.png"), "b/x.png": abs }
+
+	collisions := outputCollisions([]string{ "a/x.png", "b/x.png" }, func(path string) []string {
+		return []string{ outputs[path] }
+	})
+
+	assert.ErrorContains(t, collisions["b/x.png"], "a/x.png")
+}
+
+func TestLoadModeInputs(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	reference, palette := filepath.Join(dir, "ref.png"), filepath.Join(dir, "brand.hex")
+	assert.Nil(SaveImage(uniformImage(4, 4, white), reference))
+	assert.Nil(os.WriteFile(palette, []byte("#000000\n#ffffff\n"), 0644))
+	config := cmd.Config{ Modes: []string{ "DITHER", "POSTERIZE", "TRANSFER" }, ModeOptions: cmd.ModeOptions{ Palette: palette, Reference: reference } }
+
+	inputs, failures := LoadModeInputs(config)
+
+	assert.Empty(failures)
+	assert.Equal([]color.RGBA{ black, white }, inputs.Palette)
+	assert.InDelta(100, inputs.Reference.Mean[0], 0.5)
+}
+
+func TestProcessFiles_MissingReference(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	inputs := []string{ filepath.Join(dir, "a.png"), filepath.Join(dir, "b.png") }
+	for _, path := range inputs {
+		assert.Nil(SaveImage(uniformImage(4, 4, white), path))
+	}
+	config := cmd.Config{ InputFiles: inputs, GridRows: 2, GridCols: 2, Modes: []string{ "GRID", "TRANSFER" }, ModeOptions: cmd.ModeOptions{ Reference: filepath.Join(dir, "missing.png") }, Workers: 2, Verbosity: cmd.QUIET }
+
+	summary := ProcessFiles(config)
+
+	assert.Equal(4, summary.Total)
+	assert.Equal(2, summary.Failed)
+	for _, err := range summary.Errors {
+		var pe *ProcessError
+		assert.ErrorAs(err, &pe)
+		assert.Equal("TRANSFER", pe.Mode)
+		assert.Equal(DECODE, pe.Stage)
+		assert.ErrorContains(pe, "reference")
+	}
+}
+
+func TestEncodeReport_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	records := []Record{
+		{ Input: "a.jpg", Mode: "GRID", Output: "a-grid.jpg", Width: 10, Height: 5, GridRows: 2, GridCols: 2 },
+		{ Input: "b.jpg", Mode: "GRID", GridRows: 2, GridCols: 2, Stage: "decode", Error: "bad" },
+	}
+
+	err := EncodeReport(&buf, records, true)
+
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"output":"a-grid.jpg"`)
+	assert.Contains(t, lines[1], `"error":"bad"`)
+	assert.NotContains(t, lines[1], `"output"`)
+}
+
+func TestIsNDJSON(t *testing.T) {
+	assert.True(t, isNDJSON("run.ndjson"))
+	assert.True(t, isNDJSON("run.JSONL"))
+	assert.False(t, isNDJSON("run.json"))
+}
+
+func TestMakeSizedTiles_Remainder(t *testing.T) {
+	assert := assert.New(t)
+
+	tiles := MakeSizedTiles(70, 50, 32, 24)
+	rows, cols := gridShape(tiles)
+
+	assert.Equal(3, rows)
+	assert.Equal(3, cols)
+	assert.Equal(Tile{ 0, 0, 24, 32 }, tiles[0])
+	// remainder strips along right & bottom edges
+	assert.Equal(Tile{ 0, 64, 24, 70 }, tiles[2])
+	assert.Equal(Tile{ 48, 64, 50, 70 }, tiles[len(tiles) - 1])
+}
+
+func TestMakeSizedTiles_LargerThanImage(t *testing.T) {
+	tiles := MakeSizedTiles(20, 10, 32, 32)
+
+	assert.Equal(t, []Tile{ { 0, 0, 10, 20 } }, tiles)
+}
+
+func TestProcessFileAsync_PixelateAndSprite(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "in.png")
+	assert.Nil(SaveImage(splitImage(20, 10, 10), path))
+	config := cmd.Config{ GridRows: 2, GridCols: 4, ModeOptions: cmd.ModeOptions{ Scale: 3 } }
+
+	ch := make(chan GPResult, 2)
+	ProcessFileAsync(path, config, cmd.PIXELATE, ModeInputs{}, ch)
+	ProcessFileAsync(path, config, cmd.SPRITE, ModeInputs{}, ch)
+
+	pixelate, sprite := <-ch, <-ch
+	assert.Nil(pixelate.err)
+	assert.Equal(60, pixelate.record.Width)
+	assert.Equal(30, pixelate.record.Height)
+	assert.Nil(sprite.err)
+	assert.Equal(4, sprite.record.Width)
+	assert.Equal(2, sprite.record.Height)
+
+	img, _, err := ReadImage(sprite.output)
+	assert.Nil(err)
+	assert.Equal(white, color.RGBAModel.Convert(img.At(1, 1)))
+	assert.Equal(black, color.RGBAModel.Convert(img.At(2, 0)))
+}
+
+func TestSpriteTiles(t *testing.T) {
+	tiles := SpriteTiles(2, 3)
+
+	assert.Len(t, tiles, 6)
+	assert.Equal(t, Tile{ 1, 2, 2, 3 }, tiles[5])
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+)
+
+// ColumnLabel is spreadsheet-like: A..Z, AA, AB...
+func ColumnLabel(col int) string {
+	label := ""
+	for col >= 0 {
+		label = string(rune('A' + col % 26)) + label
+		col = col / 26 - 1
+	}
+	return label
+}
+
+// RowLabel numbers rows from 1 at the top
+func RowLabel(row int) string {
+	return strconv.Itoa(row + 1)
+}
+
+// CellLabel is chess-like column letter + row number, e.g. C7
+func CellLabel(row, col int) string {
+	return ColumnLabel(col) + RowLabel(row)
+}
+
+// gridShape counts rows & columns of row-major tiles from MakeTiles
+func gridShape(tiles []Tile) (rows, cols int) {
+	if len(tiles) == 0 {
+		return 0, 0
+	}
+	for _, t := range tiles {
+		if t.YStart != tiles[0].YStart { break }
+		cols++
+	}
+	return len(tiles) / cols, cols
+}
+
+// labelScale keeps text around 2.5% of the shorter image side
+func labelScale(bounds image.Rectangle) int {
+	return max(1, min(bounds.Dx(), bounds.Dy()) / 200)
+}
+
+// drawCellLabels writes tile name in the top-left corner of every tile
+func drawCellLabels(src image.Image, dst *image.RGBA, tiles []Tile) {
+	_, cols := gridShape(tiles)
+	maxScale := labelScale(dst.Bounds())
+	for i, t := range tiles {
+		label := CellLabel(i / cols, i % cols)
+		scale := min(maxScale, max(1, min(t.XEnd - t.XStart, t.YEnd - t.YStart) / 24))
+		pad := scale + 1
+		if textWidth(label, scale) + pad > t.XEnd - t.XStart || textHeight(scale) + pad > t.YEnd - t.YStart {
+			continue
+		}
+		c := contrastColor(AverageColor(src, Tile{ t.YStart, t.XStart, min(t.YEnd, t.YStart + textHeight(scale) + 2 * pad), min(t.XEnd, t.XStart + textWidth(label, scale) + 2 * pad) }))
+		drawText(dst, t.XStart + pad, t.YStart + pad, label, scale, c)
+	}
+}
+
+// addLabelMargin puts image into a white frame with column letters above & below
+// and row numbers on both sides
+func addLabelMargin(img *image.RGBA, tiles []Tile) *image.RGBA {
+	rows, cols := gridShape(tiles)
+	scale := labelScale(img.Bounds())
+	pad := 2 * scale
+	marginY := textHeight(scale) + 2 * pad
+	marginX := textWidth(RowLabel(rows - 1), scale) + 2 * pad
+
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx() + 2 * marginX, bounds.Dy() + 2 * marginY))
+	draw.Draw(out, out.Bounds(), &image.Uniform{ color.White }, image.Point{}, draw.Src)
+	draw.Draw(out, image.Rect(marginX, marginY, marginX + bounds.Dx(), marginY + bounds.Dy()), img, bounds.Min, draw.Src)
+
+	textColor := color.RGBA{ 0, 0, 0, 255 }
+	for col := 0; col < cols; col++ {
+		t := tiles[col]
+		label := ColumnLabel(col)
+		x := marginX + (t.XStart + t.XEnd) / 2 - textWidth(label, scale) / 2
+		drawText(out, x, pad, label, scale, textColor)
+		drawText(out, x, marginY + bounds.Dy() + pad, label, scale, textColor)
+	}
+	for row := 0; row < rows; row++ {
+		t := tiles[row * cols]
+		label := RowLabel(row)
+		y := marginY + (t.YStart + t.YEnd) / 2 - textHeight(scale) / 2
+		drawText(out, marginX - pad - textWidth(label, scale), y, label, scale, textColor)
+		drawText(out, marginX + bounds.Dx() + pad, y, label, scale, textColor)
+	}
+
+	return out
+}
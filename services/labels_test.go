@@ -0,0 +1,52 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnLabel(t *testing.T) {
+	assert.Equal(t, "A", ColumnLabel(0))
+	assert.Equal(t, "Z", ColumnLabel(25))
+	assert.Equal(t, "AA", ColumnLabel(26))
+	assert.Equal(t, "AZ", ColumnLabel(51))
+	assert.Equal(t, "BA", ColumnLabel(52))
+}
+
+func TestCellLabel(t *testing.T) {
+	assert.Equal(t, "C7", CellLabel(6, 2))
+}
+
+func TestGridShape(t *testing.T) {
+	rows, cols := gridShape(MakeTiles(100, 60, 3, 5))
+
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 5, cols)
+}
+
+func TestGridPainter_LabelMargin(t *testing.T) {
+	assert := assert.New(t)
+	src := uniformImage(400, 300, white)
+	tiles := MakeTiles(400, 300, 3, 4)
+	style := cmd.DEFAULT_GRID_STYLE
+	style.Labels = true
+
+	out := GridPainter(style)(src, tiles, image.NewRGBA(src.Bounds()), tiles)
+
+	// scale 1: 5px glyph + 2px padding on both sides, single digit row numbers
+	assert.Equal(image.Rect(0, 0, 400 + 2 * 7, 300 + 2 * 9), out.Bounds())
+}
+
+func TestDrawText(t *testing.T) {
+	dst := uniformImage(8, 5, white)
+
+	drawText(dst, 0, 0, "1-", 1, black)
+
+	assert.Equal(t, black, dst.RGBAAt(1, 0)) // top of "1"
+	assert.Equal(t, white, dst.RGBAAt(0, 0))
+	assert.Equal(t, black, dst.RGBAAt(4, 2)) // "-" starts after 1px gap
+	assert.Equal(t, white, dst.RGBAAt(4, 0))
+}
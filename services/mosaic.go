@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bytes"
+	"color-pallete/cmd"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"strconv"
+)
+
+// BRICK_SIZE is preview size of a single 1x1 plate in pixels
+const BRICK_SIZE = 20
+
+// Mosaic is a brick mosaic, every tile is a 1x1 plate of a catalog color
+type Mosaic struct {
+	CatalogPattern
+}
+
+// NewMosaic matches every tile to the nearest color of catalog
+func NewMosaic(img image.Image, tiles []Tile, catalog Catalog, metric cmd.ColorMetric) Mosaic {
+	return Mosaic{ MatchCatalog(img, tiles, catalog, 0, metric) }
+}
+
+// drawBrick paints a plate seen from above: beveled edges and a stud lit from the top-left
+func (m Mosaic) drawBrick(dst *image.RGBA, x, y, brick int) {
+	c := m.Colors[brick].Color
+	light, dark := blend(c, color.RGBA{ 255, 255, 255, 255 }, 0.35), blend(c, color.RGBA{ 0, 0, 0, 255 }, 0.35)
+	fillRect(dst, image.Rect(x, y, x + BRICK_SIZE, y + BRICK_SIZE), c)
+	fillRect(dst, image.Rect(x, y, x + BRICK_SIZE, y + 1), light)
+	fillRect(dst, image.Rect(x, y, x + 1, y + BRICK_SIZE), light)
+	fillRect(dst, image.Rect(x, y + BRICK_SIZE - 1, x + BRICK_SIZE, y + BRICK_SIZE), dark)
+	fillRect(dst, image.Rect(x + BRICK_SIZE - 1, y, x + BRICK_SIZE, y + BRICK_SIZE), dark)
+
+	center, radius := float64(BRICK_SIZE) / 2, float64(BRICK_SIZE) * 0.3
+	for py := 0; py < BRICK_SIZE; py++ {
+		for px := 0; px < BRICK_SIZE; px++ {
+			dx, dy := float64(px) + 0.5 - center, float64(py) + 0.5 - center
+			d := math.Hypot(dx, dy)
+			if d > radius || d < radius - 1.5 { continue }
+			// rim is lit on the upper left half and shaded on the lower right one
+			if dx + dy < 0 {
+				dst.SetRGBA(x + px, y + py, light)
+			} else {
+				dst.SetRGBA(x + px, y + py, dark)
+			}
+		}
+	}
+}
+
+// Preview renders plates with studs and bill of materials below
+func (m Mosaic) Preview() *image.RGBA {
+	preview := image.NewRGBA(image.Rect(0, 0, m.Cols * BRICK_SIZE, m.Rows * BRICK_SIZE))
+	for i, brick := range m.Cells {
+		m.drawBrick(preview, (i % m.Cols) * BRICK_SIZE, (i / m.Cols) * BRICK_SIZE, brick)
+	}
+
+	labels := make([]string, len(m.Colors))
+	for i, brick := range m.Colors {
+		labels[i] = fmt.Sprintf("%s %s - %d pcs", brick.Code, brick.Name, m.Counts[i])
+	}
+	return withLegend(preview, labels, BRICK_SIZE, m.drawBrick)
+}
+
+// BillOfMaterials lists plates to order per color as csv
+func (m Mosaic) BillOfMaterials() []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{ "code", "name", "hex", "count" })
+	for i, brick := range m.Colors {
+		w.Write([]string{ brick.Code, brick.Name, HexColor(brick.Color), strconv.Itoa(m.Counts[i]) })
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+func (m Mosaic) SaveBillOfMaterials(path string) error {
+	return os.WriteFile(path, m.BillOfMaterials(), 0644)
+}
+
+// Painter returns the preview, it's sized by plate count rather than source image
+func (m Mosaic) Painter() PaintFunc {
+	return func(_ image.Image, _ []Tile, _ *image.RGBA, _ []Tile) image.Image {
+		return m.Preview()
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchCatalog_NoLimit(t *testing.T) {
+	assert := assert.New(t)
+	catalog := Catalog{ Name: "test", Colors: []CatalogColor{
+		{ "w", "White", color.RGBA{ 250, 250, 250, 255 } },
+		{ "g", "Gray", gray },
+		{ "b", "Black", color.RGBA{ 5, 5, 5, 255 } },
+	} }
+
+	p := MatchCatalog(splitImage(40, 10, 10), MakeTiles(40, 10, 1, 4), catalog, 0, cmd.RGB_METRIC)
+
+	assert.Equal([]string{ "b", "w" }, []string{ p.Colors[0].Code, p.Colors[1].Code })
+	assert.Equal([]int{ 3, 1 }, p.Counts)
+	assert.Equal([]int{ 1, 0, 0, 0 }, p.Cells)
+}
+
+func TestMosaic_Preview(t *testing.T) {
+	assert := assert.New(t)
+	m := NewMosaic(uniformImage(60, 40, white), MakeTiles(60, 40, 20, 30), BRICK_COLORS, cmd.RGB_METRIC)
+
+	preview := m.Preview()
+
+	assert.Equal("1", m.Colors[0].Code)
+	assert.Equal([]int{ 600 }, m.Counts)
+	assert.Equal(30 * BRICK_SIZE, preview.Bounds().Dx())
+	assert.Greater(preview.Bounds().Dy(), 20 * BRICK_SIZE)
+	// plate face keeps catalog color, edges are shaded
+	assert.Equal(m.Colors[0].Color, preview.RGBAAt(3, 3))
+	assert.NotEqual(m.Colors[0].Color, preview.RGBAAt(BRICK_SIZE - 1, 3))
+}
+
+func TestMosaic_BillOfMaterials(t *testing.T) {
+	// brick black is bluish, by rgb distance pure black is closer to dark brown
+	m := NewMosaic(splitImage(40, 10, 10), MakeTiles(40, 10, 1, 4), BRICK_COLORS, cmd.CIEDE2000)
+
+	lines := strings.Split(strings.TrimSpace(string(m.BillOfMaterials())), "\n")
+
+	assert.Equal(t, []string{
+		"code,name,hex,count",
+		"26,Black,#1b2a34,3",
+		"1,White,#f2f3f2,1",
+	}, lines)
+}
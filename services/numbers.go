@@ -0,0 +1,333 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	OUTLINE_COLOR = color.RGBA{ 120, 120, 120, 255 }
+	NUMBER_COLOR  = color.RGBA{ 60, 60, 60, 255 }
+)
+
+// PaintByNumber is an image quantized to palette and split into regions to be painted
+type PaintByNumber struct {
+	Palette       []color.RGBA
+	Width, Height int
+	Labels        []int         // region of every pixel
+	Colors        []int         // palette index of every region
+	Anchors       []image.Point // number position of every region, farthest point from region outline
+	Depths        []int         // distance from anchor to outline, bounds number size
+}
+
+// NewPaintByNumber maps pixels to palette, then regions smaller than minRegion pixels
+// are merged into the neighbour they share the longest border with, 0 picks 0.05% of the image
+func NewPaintByNumber(img image.Image, palette []color.RGBA, metric cmd.ColorMetric, minRegion int) PaintByNumber {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	p := PaintByNumber{ Palette: palette, Width: width, Height: height }
+	if minRegion <= 0 {
+		minRegion = max(width * height / 2000, 16)
+	}
+
+	matcher := newPaletteMatcher(palette, metric)
+	indexOf := make(map[color.RGBA]int, len(palette))
+	for i := len(palette) - 1; i >= 0; i-- {
+		indexOf[palette[i]] = i
+	}
+	indexes := make([]int, width * height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBAModel.Convert(img.At(bounds.Min.X + x, bounds.Min.Y + y)).(color.RGBA)
+			c.A = 255
+			indexes[y * width + x] = indexOf[matcher.nearest(c)]
+		}
+	}
+
+	labels, count := connectRegions(indexes, width, height, 0)
+	colors := make([]int, count)
+	for px, r := range labels {
+		colors[r] = indexes[px]
+	}
+	p.Labels, p.Colors = mergeSmallRegions(labels, colors, width, height, minRegion)
+	p.Anchors, p.Depths = regionAnchors(p.Labels, len(p.Colors), width, height)
+	return p
+}
+
+// mergeSmallRegions joins regions below minSize with neighbours and relabels them compactly
+func mergeSmallRegions(labels, colors []int, width, height, minSize int) ([]int, []int) {
+	parent := make([]int, len(colors))
+	pixels := make([][]int, len(colors))
+	for i := range parent {
+		parent[i] = i
+	}
+	for px, r := range labels {
+		pixels[r] = append(pixels[r], px)
+	}
+	var find func(r int) int
+	find = func(r int) int {
+		if parent[r] != r {
+			parent[r] = find(parent[r])
+		}
+		return parent[r]
+	}
+
+	order := make([]int, len(colors))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return len(pixels[order[a]]) < len(pixels[order[b]]) })
+
+	for merged := true; merged; {
+		merged = false
+		for _, r := range order {
+			if find(r) != r || len(pixels[r]) >= minSize { continue }
+
+			borders := make(map[int]int)
+			for _, px := range pixels[r] {
+				x, y := px % width, px / width
+				for _, n := range [4][2]int{ { x - 1, y }, { x + 1, y }, { x, y - 1 }, { x, y + 1 } } {
+					if n[0] < 0 || n[1] < 0 || n[0] >= width || n[1] >= height { continue }
+					if nr := find(labels[n[1] * width + n[0]]); nr != r {
+						borders[nr]++
+					}
+				}
+			}
+			best, bestBorder := -1, 0
+			for nr, b := range borders {
+				if b > bestBorder || (b == bestBorder && nr < best) {
+					best, bestBorder = nr, b
+				}
+			}
+			// single region covering the whole image
+			if best < 0 { continue }
+
+			parent[r] = best
+			pixels[best] = append(pixels[best], pixels[r]...)
+			pixels[r] = nil
+			merged = true
+		}
+	}
+
+	compact := make(map[int]int)
+	newColors := make([]int, 0)
+	newLabels := make([]int, len(labels))
+	for px, r := range labels {
+		root := find(r)
+		i, ok := compact[root]
+		if !ok {
+			i = len(newColors)
+			compact[root] = i
+			newColors = append(newColors, colors[root])
+		}
+		newLabels[px] = i
+	}
+	return newLabels, newColors
+}
+
+// regionAnchors finds the pixel of every region farthest from its outline with 3-4 chamfer distance
+func regionAnchors(labels []int, count, width, height int) ([]image.Point, []int) {
+	dist := make([]int, width * height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			l := labels[y * width + x]
+			edge := x == 0 || y == 0 || x == width - 1 || y == height - 1 ||
+				labels[y * width + x - 1] != l || labels[y * width + x + 1] != l ||
+				labels[(y - 1) * width + x] != l || labels[(y + 1) * width + x] != l
+			dist[y * width + x] = math.MaxInt32
+			if edge {
+				dist[y * width + x] = 0
+			}
+		}
+	}
+	relax := func(x, y, nx, ny, cost int) {
+		if nx < 0 || ny < 0 || nx >= width || ny >= height { return }
+		if d := dist[ny * width + nx] + cost; d < dist[y * width + x] {
+			dist[y * width + x] = d
+		}
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			relax(x, y, x - 1, y, 3)
+			relax(x, y, x, y - 1, 3)
+			relax(x, y, x - 1, y - 1, 4)
+			relax(x, y, x + 1, y - 1, 4)
+		}
+	}
+	for y := height - 1; y >= 0; y-- {
+		for x := width - 1; x >= 0; x-- {
+			relax(x, y, x + 1, y, 3)
+			relax(x, y, x, y + 1, 3)
+			relax(x, y, x + 1, y + 1, 4)
+			relax(x, y, x - 1, y + 1, 4)
+		}
+	}
+
+	// ties on distance plateaus go to the point closest to region centroid
+	sums := make([][3]int, count)
+	for px, r := range labels {
+		sums[r] = [3]int{ sums[r][0] + px % width, sums[r][1] + px / width, sums[r][2] + 1 }
+	}
+	anchors := make([]image.Point, count)
+	depths := make([]int, count)
+	offsets := make([]int, count)
+	for i := range depths {
+		depths[i] = -1
+	}
+	for px, r := range labels {
+		x, y := px % width, px / width
+		dx, dy := x * sums[r][2] - sums[r][0], y * sums[r][2] - sums[r][1]
+		offset := dx * dx + dy * dy
+		if dist[px] > depths[r] || (dist[px] == depths[r] && offset < offsets[r]) {
+			anchors[r], depths[r], offsets[r] = image.Point{ x, y }, dist[px], offset
+		}
+	}
+	for i := range depths {
+		depths[i] /= 3
+	}
+	return anchors, depths
+}
+
+func (p PaintByNumber) isOutline(x, y int) (right, bottom bool) {
+	l := p.Labels[y * p.Width + x]
+	right = x + 1 < p.Width && p.Labels[y * p.Width + x + 1] != l
+	bottom = y + 1 < p.Height && p.Labels[(y + 1) * p.Width + x] != l
+	return right, bottom
+}
+
+// numberScale is the biggest font scale fitting into region depth, up to max
+func numberScale(label string, depth, max int) int {
+	scale := 1
+	for scale < max && textWidth(label, scale + 1) / 2 <= depth && textHeight(scale + 1) / 2 <= depth {
+		scale++
+	}
+	return scale
+}
+
+// legend lays out palette entries in rows below the sheet, returns height and entry positions
+func (p PaintByNumber) legend(scale int) (int, []image.Point) {
+	pad := 2 * scale
+	swatch := textHeight(scale) + 2 * pad
+	entryW := swatch + pad + textWidth(fmt.Sprintf("%d #000000", len(p.Palette)), scale) + 3 * pad
+	perRow := max(1, (p.Width - pad) / entryW)
+
+	positions := make([]image.Point, len(p.Palette))
+	for i := range p.Palette {
+		positions[i] = image.Point{ pad + (i % perRow) * entryW, p.Height + pad + (i / perRow) * (swatch + pad) }
+	}
+	rows := (len(p.Palette) + perRow - 1) / perRow
+	return pad + rows * (swatch + pad), positions
+}
+
+func legendLabel(i int, c color.RGBA) string {
+	return fmt.Sprintf("%d %s", i + 1, strings.ToUpper(HexColor(c)))
+}
+
+// Sheet renders outlines and region numbers on white, with palette legend below
+func (p PaintByNumber) Sheet() *image.RGBA {
+	scale := labelScale(image.Rect(0, 0, p.Width, p.Height))
+	legendH, positions := p.legend(scale)
+	out := image.NewRGBA(image.Rect(0, 0, p.Width, p.Height + legendH))
+	draw.Draw(out, out.Bounds(), &image.Uniform{ color.White }, image.Point{}, draw.Src)
+
+	for y := 0; y < p.Height; y++ {
+		for x := 0; x < p.Width; x++ {
+			if right, bottom := p.isOutline(x, y); right || bottom {
+				out.SetRGBA(x, y, OUTLINE_COLOR)
+			}
+		}
+	}
+	for r, a := range p.Anchors {
+		label := strconv.Itoa(p.Colors[r] + 1)
+		s := numberScale(label, p.Depths[r], scale)
+		drawText(out, a.X - textWidth(label, s) / 2, a.Y - textHeight(s) / 2, label, s, NUMBER_COLOR)
+	}
+
+	pad := 2 * scale
+	swatch := textHeight(scale) + 2 * pad
+	for i, c := range p.Palette {
+		pos := positions[i]
+		fillRect(out, image.Rect(pos.X, pos.Y, pos.X + swatch, pos.Y + swatch), OUTLINE_COLOR)
+		fillRect(out, image.Rect(pos.X + 1, pos.Y + 1, pos.X + swatch - 1, pos.Y + swatch - 1), c)
+		drawText(out, pos.X + swatch + pad, pos.Y + pad, legendLabel(i, c), scale, NUMBER_COLOR)
+	}
+	return out
+}
+
+// SVG is the same sheet as vector image, outline runs are merged into long lines
+func (p PaintByNumber) SVG() []byte {
+	scale := labelScale(image.Rect(0, 0, p.Width, p.Height))
+	legendH, positions := p.legend(scale)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		p.Width, p.Height + legendH, p.Width, p.Height + legendH)
+	sb.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"#ffffff\"/>\n")
+
+	sb.WriteString("<path fill=\"none\" stroke=\"" + HexColor(OUTLINE_COLOR) + "\" stroke-width=\"1\" d=\"")
+	fmt.Fprintf(&sb, "M0 0h%dv%dh-%dz", p.Width, p.Height, p.Width)
+	for y := 0; y < p.Height; y++ {
+		for x := 0; x < p.Width; {
+			run := 0
+			for x + run < p.Width {
+				if _, bottom := p.isOutline(x + run, y); !bottom { break }
+				run++
+			}
+			if run > 0 {
+				fmt.Fprintf(&sb, "M%d %dh%d", x, y + 1, run)
+			}
+			x += max(run, 1)
+		}
+	}
+	for x := 0; x < p.Width; x++ {
+		for y := 0; y < p.Height; {
+			run := 0
+			for y + run < p.Height {
+				if right, _ := p.isOutline(x, y + run); !right { break }
+				run++
+			}
+			if run > 0 {
+				fmt.Fprintf(&sb, "M%d %dv%d", x + 1, y, run)
+			}
+			y += max(run, 1)
+		}
+	}
+	sb.WriteString("\"/>\n")
+
+	sb.WriteString("<g font-family=\"sans-serif\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"" + HexColor(NUMBER_COLOR) + "\">\n")
+	for r, a := range p.Anchors {
+		size := min(max(p.Depths[r], 4), 8 * scale)
+		fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" font-size=\"%d\">%d</text>\n", a.X, a.Y, size, p.Colors[r] + 1)
+	}
+	sb.WriteString("</g>\n")
+
+	pad := 2 * scale
+	swatch := textHeight(scale) + 2 * pad
+	for i, c := range p.Palette {
+		pos := positions[i]
+		fmt.Fprintf(&sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"%s\"/>\n",
+			pos.X, pos.Y, swatch, swatch, HexColor(c), HexColor(OUTLINE_COLOR))
+		fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"%d\" dominant-baseline=\"central\" fill=\"%s\">%s</text>\n",
+			pos.X + swatch + pad, pos.Y + swatch / 2, swatch - pad, HexColor(NUMBER_COLOR), legendLabel(i, c))
+	}
+	sb.WriteString("</svg>\n")
+	return []byte(sb.String())
+}
+
+// Painter returns the sheet, pipeline destination is replaced as sheet is taller than source
+func (p PaintByNumber) Painter() PaintFunc {
+	return func(_ image.Image, _ []Tile, _ *image.RGBA, _ []Tile) image.Image {
+		return p.Sheet()
+	}
+}
+
+func (p PaintByNumber) SaveSVG(path string) error {
+	return os.WriteFile(path, p.SVG(), 0644)
+}
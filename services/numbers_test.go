@@ -0,0 +1,67 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaintByNumber_Regions(t *testing.T) {
+	assert := assert.New(t)
+	palette := []color.RGBA{ white, black }
+
+	p := NewPaintByNumber(splitImage(40, 20, 25), palette, cmd.RGB_METRIC, 0)
+
+	assert.Equal([]int{ 0, 1 }, p.Colors)
+	for r, a := range p.Anchors {
+		assert.Equal(r, p.Labels[a.Y * p.Width + a.X])
+	}
+	// farthest from outline is the middle of left part
+	assert.Equal(image.Point{ 12, 9 }, p.Anchors[0])
+}
+
+func TestNewPaintByNumber_MergesSmallRegions(t *testing.T) {
+	assert := assert.New(t)
+	img := uniformImage(40, 40, white)
+	for y := 10; y < 12; y++ {
+		for x := 10; x < 12; x++ {
+			img.Set(x, y, black)
+		}
+	}
+	palette := []color.RGBA{ white, black }
+
+	assert.Len(NewPaintByNumber(img, palette, cmd.RGB_METRIC, 1).Colors, 2)
+
+	merged := NewPaintByNumber(img, palette, cmd.RGB_METRIC, 5)
+	assert.Equal([]int{ 0 }, merged.Colors)
+	assert.Equal(0, merged.Labels[10 * 40 + 10])
+}
+
+func TestPaintByNumber_Sheet(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPaintByNumber(splitImage(40, 20, 25), []color.RGBA{ white, black }, cmd.RGB_METRIC, 0)
+
+	sheet := p.Sheet()
+
+	assert.Equal(40, sheet.Bounds().Dx())
+	assert.Greater(sheet.Bounds().Dy(), 20)
+	assert.Equal(OUTLINE_COLOR, sheet.RGBAAt(24, 0))
+	assert.Equal(color.RGBA{ 255, 255, 255, 255 }, sheet.RGBAAt(30, 0))
+}
+
+func TestPaintByNumber_SVG(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPaintByNumber(splitImage(40, 20, 25), []color.RGBA{ white, black }, cmd.RGB_METRIC, 0)
+
+	svg := string(p.SVG())
+
+	assert.True(strings.HasPrefix(svg, "<svg"))
+	// outline between halves is a single vertical run
+	assert.Contains(svg, "M25 0v20")
+	assert.Contains(svg, ">1 #FFFFFF</text>")
+	assert.Contains(svg, ">2 #000000</text>")
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"math"
+)
+
+// ModePalette is palette file colors when -k palette= is set, colors extracted from the image otherwise
+func ModePalette(img image.Image, inputs ModeInputs, options cmd.ModeOptions) []color.RGBA {
+	if options.Palette != "" {
+		return inputs.Palette
+	}
+	return QuantizePalette(img, max(options.Colors, 1))
+}
+
+// paletteMatcher finds nearest palette color under metric, source colors repeat a lot so results are cached
+type paletteMatcher struct {
+	palette []color.RGBA
+	metric  cmd.ColorMetric
+	coords  []Lab // palette in Lab / OKLab space
+	cache   map[color.RGBA]color.RGBA
+}
+
+func newPaletteMatcher(palette []color.RGBA, metric cmd.ColorMetric) *paletteMatcher {
+	m := &paletteMatcher{ palette: palette, metric: metric, coords: make([]Lab, len(palette)), cache: make(map[color.RGBA]color.RGBA) }
+	for i, c := range palette {
+		m.coords[i] = m.convert(c)
+	}
+	return m
+}
+
+func (m *paletteMatcher) convert(c color.RGBA) Lab {
+	switch m.metric {
+	case cmd.CIEDE2000:
+		return ToLab(c)
+	case cmd.OKLAB:
+		return ToOKLab(c)
+	default:
+		return Lab{ float64(c.R), float64(c.G), float64(c.B) }
+	}
+}
+
+func (m *paletteMatcher) nearest(c color.RGBA) color.RGBA {
+	if match, ok := m.cache[c]; ok {
+		return match
+	}
+	p := m.convert(c)
+	best, bestDistance := 0, math.Inf(1)
+	for i, q := range m.coords {
+		d := labDistance2(p, q)
+		if m.metric == cmd.CIEDE2000 {
+			d = CIEDE2000(p, q)
+		}
+		if d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	m.cache[c] = m.palette[best]
+	return m.palette[best]
+}
+
+// PosterizePainter replaces every source pixel with the nearest palette color
+func PosterizePainter(palette []color.RGBA, metric cmd.ColorMetric) PaintFunc {
+	return func(src image.Image, _ []Tile, dst *image.RGBA, _ []Tile) image.Image {
+		matcher := newPaletteMatcher(palette, metric)
+		bounds := src.Bounds()
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				c := color.RGBAModel.Convert(src.At(bounds.Min.X + x, bounds.Min.Y + y)).(color.RGBA)
+				c.A = 255
+				dst.SetRGBA(x, y, matcher.nearest(c))
+			}
+		}
+		return dst
+	}
+}
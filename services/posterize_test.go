@@ -0,0 +1,79 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCIEDE2000_Reference(t *testing.T) {
+	// pair 1 of Sharma, Wu, Dalal test data
+	d := CIEDE2000(Lab{ 50, 2.6772, -79.7751 }, Lab{ 50, 0, -82.7485 })
+
+	assert.InDelta(t, 2.0425, d, 0.0001)
+}
+
+func TestToOKLab_White(t *testing.T) {
+	lab := ToOKLab(white)
+
+	assert.InDelta(t, 1, lab.L, 0.001)
+	assert.InDelta(t, 0, lab.A, 0.001)
+	assert.InDelta(t, 0, lab.B, 0.001)
+}
+
+func TestPaletteMatcher_Metrics(t *testing.T) {
+	palette := []color.RGBA{ black, white, { 255, 0, 0, 255 } }
+	for _, metric := range cmd.COLOR_METRICS {
+		m := newPaletteMatcher(palette, metric)
+
+		assert.Equal(t, white, m.nearest(color.RGBA{ 230, 230, 240, 255 }), string(metric))
+		assert.Equal(t, palette[2], m.nearest(color.RGBA{ 200, 30, 20, 255 }), string(metric))
+	}
+}
+
+func TestPosterizePainter(t *testing.T) {
+	src := splitImage(10, 4, 5)
+	dst := image.NewRGBA(src.Bounds())
+	palette := []color.RGBA{ { 250, 250, 250, 255 }, { 10, 10, 10, 255 } }
+
+	PosterizePainter(palette, cmd.OKLAB)(src, nil, dst, nil)
+
+	assert.Equal(t, palette[0], dst.RGBAAt(0, 0))
+	assert.Equal(t, palette[1], dst.RGBAAt(9, 3))
+}
+
+func TestLoadSwatch_Formats(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	expected := []color.RGBA{ { 0x1b, 0x36, 0x5d, 255 }, { 0xf2, 0xa9, 0x00, 255 } }
+	for _, format := range []string{ "hex", "gpl", "json" } {
+		data, _ := Swatch{ Name: "brand", Rows: 1, Cols: 2, Colors: expected }.Encode(format)
+		path := filepath.Join(dir, "brand." + format)
+		assert.Nil(os.WriteFile(path, data, 0644))
+
+		s, err := LoadSwatch(path)
+
+		assert.Nil(err, format)
+		assert.Equal(expected, s.Colors, format)
+	}
+
+	path := filepath.Join(dir, "list.json")
+	assert.Nil(os.WriteFile(path, []byte(`["#1b365d", "f2a900"]`), 0644))
+	s, err := LoadSwatch(path)
+	assert.Nil(err)
+	assert.Equal(expected, s.Colors)
+}
+
+func TestLoadSwatch_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.hex")
+	os.WriteFile(path, []byte("\n"), 0644)
+
+	_, err := LoadSwatch(path)
+
+	assert.ErrorContains(t, err, "has no colors")
+}
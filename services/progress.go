@@ -0,0 +1,122 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const PROGRESS_BAR_WIDTH = 30
+
+// Progress reports run state to stderr, stdout is left for results
+//  quiet   - nothing, errors are still printed by caller
+//  normal  - progress bar with ETA on terminals, a line per 10% otherwise
+//  verbose - a line per file with stage timings
+type Progress struct {
+	out   io.Writer
+	level cmd.Verbosity
+	tty   bool
+
+	total    int
+	finished int
+	failed   int
+	started  time.Time
+	lastStep int // last printed 10% step when not a terminal
+}
+
+func NewProgress(level cmd.Verbosity) *Progress {
+	return &Progress{ out: os.Stderr, level: level, tty: isTerminal(os.Stderr) }
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode() & os.ModeCharDevice != 0
+}
+
+func (p *Progress) Start(total int) {
+	p.total = total
+	p.started = time.Now()
+	if p.level == cmd.NORMAL && p.tty {
+		p.drawBar()
+	}
+}
+
+// Step is called once per finished item, record is nil for commands without stage timings
+func (p *Progress) Step(label, path string, record *Record, err error) {
+	p.finished++
+	if err != nil {
+		p.failed++
+	}
+
+	switch p.level {
+	case cmd.VERBOSE:
+		p.printStep(label, path, record, err)
+	case cmd.NORMAL:
+		if p.tty {
+			p.drawBar()
+			return
+		}
+		step := p.finished * 10 / max(p.total, 1)
+		if step > p.lastStep {
+			p.lastStep = step
+			fmt.Fprintf(p.out, "progress: %d / %d (%d%%)\n", p.finished, p.total, p.percent())
+		}
+	}
+}
+
+func (p *Progress) Finish() {
+	if p.level == cmd.QUIET {
+		return
+	}
+	if p.level == cmd.NORMAL && p.tty {
+		fmt.Fprintln(p.out)
+	}
+	elapsed := time.Since(p.started)
+	fmt.Fprintf(p.out, "summary: %d / %d done, %d failed in %s (%.1f files/s)\n",
+		p.finished - p.failed, p.total, p.failed, elapsed.Round(time.Millisecond), p.throughput())
+}
+
+func (p *Progress) printStep(label, path string, record *Record, err error) {
+	status := "done"
+	if err != nil {
+		status = "failed"
+	}
+	line := fmt.Sprintf("%s: [ %s ] for %s (%d / %d)", status, label, path, p.finished, p.total)
+	if record != nil {
+		if record.Output != "" {
+			line += " -> " + record.Output
+		}
+		line += fmt.Sprintf(" decode %.1fms, paint %.1fms, encode %.1fms", record.Timings.Decode, record.Timings.Paint, record.Timings.Encode)
+	}
+	fmt.Fprintln(p.out, line)
+}
+
+func (p *Progress) drawBar() {
+	filled := PROGRESS_BAR_WIDTH * p.finished / max(p.total, 1)
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", PROGRESS_BAR_WIDTH - filled)
+	eta := "--"
+	if p.finished > 0 {
+		elapsed := time.Since(p.started)
+		remaining := elapsed / time.Duration(p.finished) * time.Duration(p.total - p.finished)
+		eta = remaining.Round(time.Second).String()
+	}
+	fmt.Fprintf(p.out, "\r[%s] %d / %d (%d%%) %.1f files/s ETA %s   ", bar, p.finished, p.total, p.percent(), p.throughput(), eta)
+}
+
+func (p *Progress) percent() int {
+	return p.finished * 100 / max(p.total, 1)
+}
+
+func (p *Progress) throughput() float64 {
+	seconds := time.Since(p.started).Seconds()
+	if seconds == 0 {
+		return 0
+	}
+	return float64(p.finished) / seconds
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"bytes"
+	"color-pallete/cmd"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProgress(level cmd.Verbosity, tty bool) (*Progress, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return &Progress{ out: &buf, level: level, tty: tty }, &buf
+}
+
+func TestProgress_Quiet(t *testing.T) {
+	p, buf := newTestProgress(cmd.QUIET, false)
+
+	p.Start(2)
+	p.Step("GRID", "a.jpg", nil, nil)
+	p.Step("GRID", "b.jpg", nil, errors.New("bad"))
+	p.Finish()
+
+	assert.Equal(t, "", buf.String())
+}
+
+func TestProgress_NormalNoTerminal(t *testing.T) {
+	p, buf := newTestProgress(cmd.NORMAL, false)
+
+	p.Start(20)
+	for i := 0; i < 20; i++ {
+		p.Step("GRID", "a.jpg", nil, nil)
+	}
+	p.Finish()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 11) // a line per 10% + summary
+	assert.Equal(t, "progress: 2 / 20 (10%)", lines[0])
+	assert.Contains(t, lines[10], "summary: 20 / 20 done, 0 failed")
+}
+
+func TestProgress_Verbose(t *testing.T) {
+	p, buf := newTestProgress(cmd.VERBOSE, false)
+	record := &Record{ Output: "a-grid.jpg", Timings: Timings{ Decode: 1, Paint: 2.5, Encode: 3 } }
+
+	p.Start(1)
+	p.Step("GRID", "a.jpg", record, nil)
+
+	assert.Equal(t, "done: [ GRID ] for a.jpg (1 / 1) -> a-grid.jpg decode 1.0ms, paint 2.5ms, encode 3.0ms\n", buf.String())
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"image"
+	"math"
+)
+
+// integral holds summed-area tables of channels and their squares,
+// so mean and variance of any tile cost a few lookups
+type integral struct {
+	width int
+	sums  [6][]uint64 // r, g, b, r², g², b²
+}
+
+func newIntegral(img image.Image) integral {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	in := integral{ width: w + 1 }
+	for i := range in.sums {
+		in.sums[i] = make([]uint64, (w + 1) * (h + 1))
+	}
+
+	for y := 1; y <= h; y++ {
+		for x := 1; x <= w; x++ {
+			r, g, b, _ := img.At(bounds.Min.X + x - 1, bounds.Min.Y + y - 1).RGBA()
+			values := [6]uint64{ uint64(r >> 8), uint64(g >> 8), uint64(b >> 8) }
+			for c := 0; c < 3; c++ {
+				values[c + 3] = values[c] * values[c]
+			}
+			p := y * in.width + x
+			for i, v := range values {
+				in.sums[i][p] = v + in.sums[i][p - 1] + in.sums[i][p - in.width] - in.sums[i][p - in.width - 1]
+			}
+		}
+	}
+	return in
+}
+
+func (in integral) sum(i int, t Tile) float64 {
+	s := in.sums[i]
+	return float64(s[t.YEnd * in.width + t.XEnd] + s[t.YStart * in.width + t.XStart] -
+		s[t.YStart * in.width + t.XEnd] - s[t.YEnd * in.width + t.XStart])
+}
+
+// deviation is color standard deviation of a tile, averaged over channels
+func (in integral) deviation(t Tile) float64 {
+	n := float64((t.XEnd - t.XStart) * (t.YEnd - t.YStart))
+	if n == 0 {
+		return 0
+	}
+	variance := 0.0
+	for c := 0; c < 3; c++ {
+		mean := in.sum(c, t) / n
+		variance += in.sum(c + 3, t) / n - mean * mean
+	}
+	return math.Sqrt(math.Max(variance / 3, 0))
+}
+
+// QuadTiles splits every tile into quarters while its color deviation is above threshold,
+// depth limits number of splits, tiles of 1px width or height are kept
+func QuadTiles(img image.Image, tiles []Tile, depth int, threshold float64) []Tile {
+	in := newIntegral(img)
+	result := make([]Tile, 0, len(tiles))
+
+	var split func(t Tile, level int)
+	split = func(t Tile, level int) {
+		w, h := t.XEnd - t.XStart, t.YEnd - t.YStart
+		if level >= depth || w < 2 || h < 2 || in.deviation(t) <= threshold {
+			result = append(result, t)
+			return
+		}
+		midY, midX := t.YStart + h / 2, t.XStart + w / 2
+		split(Tile{ t.YStart, t.XStart, midY, midX }, level + 1)
+		split(Tile{ t.YStart, midX, midY, t.XEnd }, level + 1)
+		split(Tile{ midY, t.XStart, t.YEnd, midX }, level + 1)
+		split(Tile{ midY, midX, t.YEnd, t.XEnd }, level + 1)
+	}
+	for _, t := range tiles {
+		split(t, 0)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuadTiles_FlatImageNotSplit(t *testing.T) {
+	tiles := QuadTiles(uniformImage(64, 64, white), []Tile{ { 0, 0, 64, 64 } }, 6, 10)
+
+	assert.Equal(t, []Tile{ { 0, 0, 64, 64 } }, tiles)
+}
+
+func TestQuadTiles_SplitsAlongEdge(t *testing.T) {
+	assert := assert.New(t)
+	src := splitImage(64, 64, 40)
+
+	tiles := QuadTiles(src, []Tile{ { 0, 0, 64, 64 } }, 6, 10)
+
+	// left half is flat, right half gets split down to the edge at x = 40
+	assert.Contains(tiles, Tile{ 0, 0, 32, 32 })
+	assert.Contains(tiles, Tile{ 32, 0, 64, 32 })
+	for _, tile := range tiles {
+		assert.False(tile.XStart < 40 && tile.XEnd > 40, "tile %v crosses the edge", tile)
+	}
+}
+
+func TestQuadTiles_DepthLimit(t *testing.T) {
+	src := splitImage(64, 64, 33)
+
+	tiles := QuadTiles(src, []Tile{ { 0, 0, 64, 64 } }, 1, 0)
+
+	assert.Len(t, tiles, 4)
+}
+
+func TestIntegral_Deviation(t *testing.T) {
+	in := newIntegral(splitImage(10, 10, 5))
+
+	assert.InDelta(t, 0, in.deviation(Tile{ 0, 0, 10, 5 }), 0.001)
+	assert.InDelta(t, 127.5, in.deviation(Tile{ 0, 0, 10, 10 }), 0.001)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Timings struct {
+	Decode float64 `json:"decode"`
+	Paint  float64 `json:"paint"`
+	Encode float64 `json:"encode"`
+}
+
+// Record describes a single (input, mode) pair of a run
+type Record struct {
+	Input    string  `json:"input"`
+	Mode     string  `json:"mode"`
+	Output   string  `json:"output,omitempty"`
+	Width    int     `json:"width,omitempty"`
+	Height   int     `json:"height,omitempty"`
+	GridRows int     `json:"gridRows"`
+	GridCols int     `json:"gridCols"`
+	Timings  Timings `json:"timingsMs"`
+	Stage    string  `json:"stage,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// isNDJSON picks one record per line for .ndjson / .jsonl reports, json array otherwise
+func isNDJSON(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".ndjson" || ext == ".jsonl"
+}
+
+func EncodeReport(w io.Writer, records []Record, ndjson bool) error {
+	encoder := json.NewEncoder(w)
+	if ndjson {
+		for _, r := range records {
+			if err := encoder.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+func WriteReport(config cmd.Config, records []Record) error {
+	if config.ReportPath == "" {
+		return nil
+	}
+	if config.ReportPath == cmd.STDIO {
+		return EncodeReport(os.Stdout, records, false)
+	}
+
+	file, err := os.Create(config.ReportPath)
+	if err != nil {
+		return errors.New("can't write report: " + err.Error())
+	}
+	defer file.Close()
+
+	return EncodeReport(file, records, isNDJSON(config.ReportPath))
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"math"
+)
+
+type Point struct {
+	X, Y float64
+}
+
+// Shape is a polygon cell of hex / triangle / brick tessellation
+type Shape []Point
+
+// Contains tests point against polygon with crossing number rule,
+// points on an edge shared by two shapes belong to exactly one of them
+func (s Shape) Contains(x, y float64) bool {
+	in := false
+	for i, j := 0, len(s) - 1; i < len(s); j, i = i, i + 1 {
+		a, b := s[i], s[j]
+		if (a.Y > y) != (b.Y > y) && x < (b.X - a.X) * (y - a.Y) / (b.Y - a.Y) + a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Bounds is pixel area covering the shape, clipped to width x height
+func (s Shape) Bounds(width, height int) Tile {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range s {
+		minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+		maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+	}
+	return Tile{
+		YStart: max(int(math.Floor(minY)), 0),
+		XStart: max(int(math.Floor(minX)), 0),
+		YEnd:   min(int(math.Ceil(maxY)), height),
+		XEnd:   min(int(math.Ceil(maxX)), width),
+	}
+}
+
+// Tessellate covers image with polygon cells, cell size is the first tile of rect grid
+func Tessellate(width, height int, config cmd.Config) []Shape {
+	tiles := ImageTiles(width, height, config)
+	if len(tiles) == 0 {
+		return nil
+	}
+	cellW, cellH := float64(tiles[0].XEnd - tiles[0].XStart), float64(tiles[0].YEnd - tiles[0].YStart)
+
+	var shapes []Shape
+	switch config.Tessellation {
+	case cmd.HEX:
+		shapes = HexShapes(width, height, cellW, cellH)
+	case cmd.TRIANGLE:
+		shapes = TriangleShapes(width, height, cellW, cellH)
+	case cmd.BRICK:
+		shapes = BrickShapes(width, height, cellW, cellH)
+	}
+
+	// drop cells lying completely outside of the image
+	visible := shapes[:0]
+	for _, s := range shapes {
+		b := s.Bounds(width, height)
+		if b.XEnd > b.XStart && b.YEnd > b.YStart {
+			visible = append(visible, s)
+		}
+	}
+	return visible
+}
+
+// HexShapes are pointy-top hexagons cellW wide, rows are cellH apart
+func HexShapes(width, height int, cellW, cellH float64) []Shape {
+	hexH := cellH * 4 / 3
+	shapes := make([]Shape, 0)
+	for r := -1; float64(r) * cellH < float64(height); r++ {
+		cy := float64(r) * cellH + cellH / 2
+		for c := -1; float64(c) * cellW < float64(width); c++ {
+			cx := float64(c) * cellW + cellW / 2 + float64(r & 1) * cellW / 2
+			shapes = append(shapes, Shape{
+				{ cx, cy - hexH / 2 },
+				{ cx + cellW / 2, cy - hexH / 4 },
+				{ cx + cellW / 2, cy + hexH / 4 },
+				{ cx, cy + hexH / 2 },
+				{ cx - cellW / 2, cy + hexH / 4 },
+				{ cx - cellW / 2, cy - hexH / 4 },
+			})
+		}
+	}
+	return shapes
+}
+
+// TriangleShapes alternate up / down triangles with cellW base and cellH height
+func TriangleShapes(width, height int, cellW, cellH float64) []Shape {
+	shapes := make([]Shape, 0)
+	for r := 0; float64(r) * cellH < float64(height); r++ {
+		top, bottom := float64(r) * cellH, float64(r + 1) * cellH
+		for i := -1; float64(i) * cellW / 2 < float64(width); i++ {
+			left := float64(i) * cellW / 2
+			if (i + r) & 1 == 0 {
+				shapes = append(shapes, Shape{ { left, bottom }, { left + cellW / 2, top }, { left + cellW, bottom } })
+			} else {
+				shapes = append(shapes, Shape{ { left, top }, { left + cellW, top }, { left + cellW / 2, bottom } })
+			}
+		}
+	}
+	return shapes
+}
+
+// BrickShapes are cellW x cellH rectangles, odd rows shifted by half a cell
+func BrickShapes(width, height int, cellW, cellH float64) []Shape {
+	shapes := make([]Shape, 0)
+	for r := 0; float64(r) * cellH < float64(height); r++ {
+		top, bottom := float64(r) * cellH, float64(r + 1) * cellH
+		for left := -float64(r & 1) * cellW / 2; left < float64(width); left += cellW {
+			shapes = append(shapes, Shape{ { left, top }, { left + cellW, top }, { left + cellW, bottom }, { left, bottom } })
+		}
+	}
+	return shapes
+}
+
+func ScaleShapes(shapes []Shape, fromW, fromH, toW, toH int) []Shape {
+	sx, sy := float64(toW) / float64(fromW), float64(toH) / float64(fromH)
+	scaled := make([]Shape, len(shapes))
+	for i, s := range shapes {
+		scaled[i] = make(Shape, len(s))
+		for j, p := range s {
+			scaled[i][j] = Point{ p.X * sx, p.Y * sy }
+		}
+	}
+	return scaled
+}
+
+// OwnerMap assigns every pixel to the shape containing its center, -1 when none does
+func OwnerMap(shapes []Shape, width, height int) []int {
+	owners := make([]int, width * height)
+	for i := range owners {
+		owners[i] = -1
+	}
+	for i, s := range shapes {
+		b := s.Bounds(width, height)
+		for y := b.YStart; y < b.YEnd; y++ {
+			for x := b.XStart; x < b.XEnd; x++ {
+				if owners[y * width + x] == -1 && s.Contains(float64(x) + 0.5, float64(y) + 0.5) {
+					owners[y * width + x] = i
+				}
+			}
+		}
+	}
+	// centers exactly on a vertex may be missed, they join left / upper neighbour
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			switch {
+			case owners[y * width + x] != -1:
+			case x > 0:
+				owners[y * width + x] = owners[y * width + x - 1]
+			case y > 0:
+				owners[y * width + x] = owners[(y - 1) * width + x]
+			}
+		}
+	}
+	return owners
+}
+
+// shapeColors averages source pixels owned by every shape
+func shapeColors(src image.Image, owners []int, count int) []color.RGBA {
+	bounds := src.Bounds()
+	totals := make([][4]uint64, count)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			i := owners[y * bounds.Dx() + x]
+			if i < 0 { continue }
+			r, g, b, _ := src.At(bounds.Min.X + x, bounds.Min.Y + y).RGBA()
+			totals[i][0] += uint64(r >> 8)
+			totals[i][1] += uint64(g >> 8)
+			totals[i][2] += uint64(b >> 8)
+			totals[i][3]++
+		}
+	}
+
+	colors := make([]color.RGBA, count)
+	for i, t := range totals {
+		colors[i] = color.RGBA{ 0, 0, 0, 255 }
+		if t[3] > 0 {
+			colors[i] = color.RGBA{ uint8(t[0] / t[3]), uint8(t[1] / t[3]), uint8(t[2] / t[3]), 255 }
+		}
+	}
+	return colors
+}
+
+// Regions labels every pixel with index of the cell it belongs to, -1 for none
+type Regions struct {
+	Owners        []int
+	Count         int
+	Width, Height int
+}
+
+// ImageRegions splits image into polygon cells or content-aware superpixels
+func ImageRegions(img image.Image, config cmd.Config) Regions {
+	bounds := img.Bounds()
+	switch config.Tessellation {
+	case cmd.SLIC, cmd.VORONOI:
+		return Superpixels(img, config)
+	}
+	shapes := Tessellate(bounds.Dx(), bounds.Dy(), config)
+	return Regions{ OwnerMap(shapes, bounds.Dx(), bounds.Dy()), len(shapes), bounds.Dx(), bounds.Dy() }
+}
+
+// OutputRegions maps regions onto output resolution,
+// polygons are rasterized again to keep edges sharp, superpixels are scaled by nearest pixel
+func OutputRegions(img image.Image, regions Regions, width, height int, config cmd.Config) Regions {
+	if width == regions.Width && height == regions.Height {
+		return regions
+	}
+	switch config.Tessellation {
+	case cmd.SLIC, cmd.VORONOI:
+		owners := make([]int, width * height)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				owners[y * width + x] = regions.Owners[(y * regions.Height / height) * regions.Width + x * regions.Width / width]
+			}
+		}
+		return Regions{ owners, regions.Count, width, height }
+	}
+	bounds := img.Bounds()
+	shapes := ScaleShapes(Tessellate(bounds.Dx(), bounds.Dy(), config), bounds.Dx(), bounds.Dy(), width, height)
+	return Regions{ OwnerMap(shapes, width, height), len(shapes), width, height }
+}
+
+// RegionPainter renders cells, PALLETE fills them with average color, GRID outlines them.
+// tiles passed by the pipeline are ignored, outRegions are regions at output resolution
+func RegionPainter(mode cmd.Mode, regions, outRegions Regions, style cmd.GridStyle) PaintFunc {
+	if style == (cmd.GridStyle{}) {
+		style = cmd.DEFAULT_GRID_STYLE
+	}
+	return func(src image.Image, _ []Tile, dst *image.RGBA, _ []Tile) image.Image {
+		colors := shapeColors(src, regions.Owners, regions.Count)
+		if mode == cmd.GRID {
+			return drawShapeGrid(src, regions.Owners, colors, dst, style)
+		}
+
+		for y := 0; y < outRegions.Height; y++ {
+			for x := 0; x < outRegions.Width; x++ {
+				if i := outRegions.Owners[y * outRegions.Width + x]; i >= 0 {
+					dst.SetRGBA(x, y, colors[i])
+				}
+			}
+		}
+		return dst
+	}
+}
+
+// drawShapeGrid paints lines along pixels whose right or lower neighbour belongs to another shape
+func drawShapeGrid(src image.Image, owners []int, colors []color.RGBA, dst *image.RGBA, style cmd.GridStyle) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	lineColor, err := cmd.ParseHexColor(style.Color)
+	if err != nil {
+		lineColor = color.RGBA{ 0, 0, 0, 255 }
+	}
+	auto := style.Color == cmd.AUTO_COLOR
+	lineWidth := max(style.Width, 1)
+	before, after := (lineWidth + 1) / 2, lineWidth / 2
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dst.Set(x, y, src.At(x, y))
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			owner := owners[y * width + x]
+			edge := (x + 1 < width && owners[y * width + x + 1] != owner) ||
+				(y + 1 < height && owners[(y + 1) * width + x] != owner)
+			if !edge { continue }
+
+			c := lineColor
+			if auto && owner >= 0 {
+				c = contrastColor(colors[owner])
+			}
+			for ly := y - before + 1; ly <= y + after; ly++ {
+				for lx := x - before + 1; lx <= x + after; lx++ {
+					paintLinePixel(src, dst, lx, ly, x + y, c, style)
+				}
+			}
+		}
+	}
+
+	if style.Border {
+		c := lineColor
+		if auto {
+			c = contrastColor(AverageColor(src, Tile{ 0, 0, height, width }))
+		}
+		drawBorder(src, dst, c, style)
+	}
+	return dst
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShape_Contains(t *testing.T) {
+	assert := assert.New(t)
+	triangle := Shape{ { 0, 10 }, { 5, 0 }, { 10, 10 } }
+
+	assert.True(triangle.Contains(5, 5))
+	assert.False(triangle.Contains(1, 1))
+	assert.False(triangle.Contains(11, 9))
+}
+
+func TestShape_BoundsClipped(t *testing.T) {
+	hex := HexShapes(20, 20, 10, 10)[0]
+
+	assert.Equal(t, Tile{ 0, 0, 2, 5 }, hex.Bounds(20, 20))
+}
+
+func TestOwnerMap_CoversImage(t *testing.T) {
+	for _, tess := range []cmd.Tessellation{ cmd.HEX, cmd.TRIANGLE, cmd.BRICK } {
+		config := cmd.Config{ GridRows: 5, GridCols: 7, Tessellation: tess }
+		shapes := Tessellate(70, 45, config)
+
+		owners := OwnerMap(shapes, 70, 45)
+
+		assert.NotContains(t, owners, -1, string(tess))
+	}
+}
+
+func TestBrickShapes_OddRowsShifted(t *testing.T) {
+	shapes := BrickShapes(20, 20, 10, 10)
+
+	assert.Equal(t, Shape{ { 0, 0 }, { 10, 0 }, { 10, 10 }, { 0, 10 } }, shapes[0])
+	assert.Equal(t, Shape{ { -5, 10 }, { 5, 10 }, { 5, 20 }, { -5, 20 } }, shapes[2])
+}
+
+func TestRegionPainter_Pallete(t *testing.T) {
+	src := uniformImage(40, 30, white)
+	config := cmd.Config{ GridRows: 3, GridCols: 4, Tessellation: cmd.TRIANGLE }
+	regions := ImageRegions(src, config)
+
+	out := RegionPainter(cmd.PALLETE, regions, regions, cmd.GridStyle{})(src, nil, image.NewRGBA(src.Bounds()), nil)
+
+	assert.Equal(t, white, out.At(20, 15))
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/nickalie/go-webpbin"
+)
+
+// stdin can be read once, every mode of "-" input decodes the same bytes
+var stdinOnce sync.Once
+var stdinData []byte
+var stdinErr error
+
+func readStdin() ([]byte, error) {
+	stdinOnce.Do(func() {
+		stdinData, stdinErr = io.ReadAll(os.Stdin)
+	})
+	return stdinData, stdinErr
+}
+
+// isWebP sniffs RIFF container header, image.Decode handles the rest of formats
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+func decodeStdin() (image.Image, string, error) {
+	data, err := readStdin()
+	if err != nil {
+		return nil, "", err
+	}
+	if isWebP(data) {
+		img, err := webpbin.Decode(bytes.NewReader(data))
+		return img, "webp", err
+	}
+	return image.Decode(bytes.NewReader(data))
+}
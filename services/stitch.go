@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bytes"
+	"color-pallete/cmd"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"os"
+	"strconv"
+)
+
+const (
+	STITCH_SIZE  = 16 // chart cell of a single stitch in pixels
+	STITCH_MAJOR = 10 // every 10th chart line is bold, as on printed patterns
+	// full crosses one 8m skein covers with 2 strands on 14 count aida, approximate
+	STITCHES_PER_SKEIN = 1800
+)
+
+// symbols are picked to be told apart in the 3x5 font, O / 0 and I / 1 pairs are left out
+var STITCH_SYMBOLS = []rune("X+#=/%:-()ABCDEFGHJKLMNPQRSTUVWYZ23456789")
+
+var (
+	STITCH_MINOR_STYLE = cmd.GridStyle{ Color: "#9a9a9a", Width: 1, Opacity: 1 }
+	STITCH_MAJOR_STYLE = cmd.GridStyle{ Color: "#000000", Width: 2, Opacity: 1, Border: true }
+)
+
+// Stitches is a cross-stitch pattern, every tile is one stitch of a catalog thread
+type Stitches struct {
+	Catalog    string
+	Rows, Cols int
+	Threads    []CatalogColor // used threads, most stitched first
+	Counts     []int          // stitches of every thread
+	Cells      []int          // thread of every stitch, row-major
+}
+
+// NewStitches reduces tile colors to at most colors threads of catalog, see MatchCatalog
+func NewStitches(img image.Image, tiles []Tile, catalog Catalog, colors int, metric cmd.ColorMetric) Stitches {
+	p := MatchCatalog(img, tiles, catalog, max(colors, 1), metric)
+	return Stitches{ Catalog: p.Catalog, Rows: p.Rows, Cols: p.Cols, Threads: p.Colors, Counts: p.Counts, Cells: p.Cells }
+}
+
+// Symbol of i-th thread, two letters once single symbols run out
+func (s Stitches) Symbol(i int) string {
+	if i < len(STITCH_SYMBOLS) {
+		return string(STITCH_SYMBOLS[i])
+	}
+	return ColumnLabel(i)
+}
+
+func (s Stitches) Skeins(i int) int {
+	return (s.Counts[i] + STITCHES_PER_SKEIN - 1) / STITCHES_PER_SKEIN
+}
+
+// drawStitch fills cell with thread color and symbol in contrasting color
+func (s Stitches) drawStitch(dst *image.RGBA, x, y, thread int) {
+	c := s.Threads[thread].Color
+	fillRect(dst, image.Rect(x, y, x + STITCH_SIZE, y + STITCH_SIZE), c)
+	symbol := s.Symbol(thread)
+	scale := 2
+	if textWidth(symbol, scale) > STITCH_SIZE - 4 {
+		scale = 1
+	}
+	drawText(dst, x + (STITCH_SIZE - textWidth(symbol, scale)) / 2, y + (STITCH_SIZE - textHeight(scale)) / 2, symbol, scale, contrastColor(c))
+}
+
+// Chart draws symbol grid with thin line around every stitch, bold line every 10 stitches
+// and legend of threads with stitch counts below
+func (s Stitches) Chart() *image.RGBA {
+	w, h := s.Cols * STITCH_SIZE, s.Rows * STITCH_SIZE
+	cells := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i, thread := range s.Cells {
+		s.drawStitch(cells, (i % s.Cols) * STITCH_SIZE, (i / s.Cols) * STITCH_SIZE, thread)
+	}
+	minor := image.NewRGBA(cells.Bounds())
+	DrawStyledGrid(cells, MakeSizedTiles(w, h, STITCH_SIZE, STITCH_SIZE), minor, STITCH_MINOR_STYLE)
+	major := image.NewRGBA(cells.Bounds())
+	DrawStyledGrid(minor, MakeSizedTiles(w, h, STITCH_MAJOR * STITCH_SIZE, STITCH_MAJOR * STITCH_SIZE), major, STITCH_MAJOR_STYLE)
+
+	labels := make([]string, len(s.Threads))
+	for i, thread := range s.Threads {
+		labels[i] = fmt.Sprintf("%s %s %s - %d st / %d sk", s.Catalog, thread.Code, thread.Name, s.Counts[i], s.Skeins(i))
+	}
+	return withLegend(major, labels, STITCH_SIZE, s.drawStitch)
+}
+
+// Legend is a shopping list of threads as csv
+func (s Stitches) Legend() []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{ "symbol", "brand", "code", "name", "hex", "stitches", "skeins" })
+	for i, thread := range s.Threads {
+		w.Write([]string{ s.Symbol(i), s.Catalog, thread.Code, thread.Name, HexColor(thread.Color), strconv.Itoa(s.Counts[i]), strconv.Itoa(s.Skeins(i)) })
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+func (s Stitches) SaveLegend(path string) error {
+	return os.WriteFile(path, s.Legend(), 0644)
+}
+
+// Painter returns the chart, it's sized by stitch count rather than source image
+func (s Stitches) Painter() PaintFunc {
+	return func(_ image.Image, _ []Tile, _ *image.RGBA, _ []Tile) image.Image {
+		return s.Chart()
+	}
+}
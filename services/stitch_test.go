@@ -0,0 +1,65 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStitches_MatchesCatalog(t *testing.T) {
+	assert := assert.New(t)
+	img := splitImage(30, 10, 10)
+
+	s := NewStitches(img, MakeTiles(30, 10, 1, 3), DMC_FLOSS, 8, cmd.RGB_METRIC)
+
+	assert.Equal(1, s.Rows)
+	assert.Equal(3, s.Cols)
+	assert.Len(s.Threads, 2)
+	// black tiles outnumber white ones, so black goes first
+	assert.Equal("310", s.Threads[0].Code)
+	assert.Equal("B5200", s.Threads[1].Code)
+	assert.Equal([]int{ 2, 1 }, s.Counts)
+	assert.Equal([]int{ 1, 0, 0 }, s.Cells)
+}
+
+func TestNewStitches_LimitsThreads(t *testing.T) {
+	img := splitImage(30, 10, 10)
+
+	s := NewStitches(img, MakeTiles(30, 10, 1, 3), DMC_FLOSS, 1, cmd.RGB_METRIC)
+
+	assert.Len(t, s.Threads, 1)
+	assert.Equal(t, []int{ 3 }, s.Counts)
+}
+
+func TestStitches_Symbol(t *testing.T) {
+	s := Stitches{}
+
+	assert.Equal(t, "X", s.Symbol(0))
+	assert.Len(t, s.Symbol(len(STITCH_SYMBOLS)), 2)
+}
+
+func TestStitches_Chart(t *testing.T) {
+	assert := assert.New(t)
+	s := NewStitches(splitImage(300, 100, 100), MakeTiles(300, 100, 12, 30), DMC_FLOSS, 8, cmd.RGB_METRIC)
+
+	chart := s.Chart()
+
+	assert.Equal(30 * STITCH_SIZE, chart.Bounds().Dx())
+	assert.Greater(chart.Bounds().Dy(), 12 * STITCH_SIZE)
+	// bold line closes the 10th stitch
+	assert.Equal(uint8(0), chart.RGBAAt(10 * STITCH_SIZE - 1, 1).R)
+}
+
+func TestStitches_Legend(t *testing.T) {
+	s := NewStitches(splitImage(30, 10, 10), MakeTiles(30, 10, 1, 3), DMC_FLOSS, 8, cmd.RGB_METRIC)
+
+	lines := strings.Split(strings.TrimSpace(string(s.Legend())), "\n")
+
+	assert.Equal(t, []string{
+		"symbol,brand,code,name,hex,stitches,skeins",
+		"X,DMC,310,Black,#000000,2,1",
+		"+,DMC,B5200,Snow White,#ffffff,1,1",
+	}, lines)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"math"
+	"math/rand/v2"
+)
+
+const (
+	SLIC_ITERATIONS  = 10
+	SLIC_COMPACTNESS = 10.0 // higher keeps superpixels closer to grid cells, lower follows color edges
+)
+
+type seed struct {
+	lab  Lab
+	x, y float64
+}
+
+// Superpixels segments image into about as many regions as the rect grid has tiles.
+// voronoi - nearest seed sampled in every grid cell, slic - k-means clustering over color and position
+func Superpixels(img image.Image, config cmd.Config) Regions {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	tiles := ImageTiles(width, height, config)
+	if len(tiles) == 0 || width == 0 || height == 0 {
+		return Regions{ make([]int, width * height), 0, width, height }
+	}
+	step := float64(max(tiles[0].XEnd - tiles[0].XStart, tiles[0].YEnd - tiles[0].YStart))
+
+	labs := make([]Lab, width * height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			labs[y * width + x] = ToLab(img.At(bounds.Min.X + x, bounds.Min.Y + y))
+		}
+	}
+
+	seeds := sampleSeeds(labs, width, height, tiles, config.Tessellation == cmd.VORONOI)
+	var owners []int
+	if config.Tessellation == cmd.VORONOI {
+		owners = assignSeeds(labs, width, height, seeds, step, 0)
+	} else {
+		for i := 0; i < SLIC_ITERATIONS; i++ {
+			owners = assignSeeds(labs, width, height, seeds, step, SLIC_COMPACTNESS)
+			seeds = updateSeeds(labs, width, owners, seeds)
+		}
+	}
+
+	owners, count := connectRegions(owners, width, height, int(step * step / 4))
+	return Regions{ owners, count, width, height }
+}
+
+// sampleSeeds places one seed per grid tile, jittered inside the tile when random is set,
+// then moved to the lowest color gradient nearby so seeds don't start on an edge
+func sampleSeeds(labs []Lab, width, height int, tiles []Tile, random bool) []seed {
+	rnd := rand.New(rand.NewPCG(uint64(width), uint64(height)))
+	seeds := make([]seed, 0, len(tiles))
+	for _, t := range tiles {
+		x, y := (t.XStart + t.XEnd) / 2, (t.YStart + t.YEnd) / 2
+		if random {
+			x, y = t.XStart + rnd.IntN(t.XEnd - t.XStart), t.YStart + rnd.IntN(t.YEnd - t.YStart)
+		}
+
+		bestX, bestY, best := x, y, math.Inf(1)
+		for ny := max(y - 1, 1); ny <= min(y + 1, height - 2); ny++ {
+			for nx := max(x - 1, 1); nx <= min(x + 1, width - 2); nx++ {
+				if g := gradient(labs, width, nx, ny); g < best {
+					bestX, bestY, best = nx, ny, g
+				}
+			}
+		}
+		seeds = append(seeds, seed{ labs[bestY * width + bestX], float64(bestX), float64(bestY) })
+	}
+	return seeds
+}
+
+func gradient(labs []Lab, width, x, y int) float64 {
+	return labDistance2(labs[y * width + x + 1], labs[y * width + x - 1]) +
+		labDistance2(labs[(y + 1) * width + x], labs[(y - 1) * width + x])
+}
+
+func labDistance2(a, b Lab) float64 {
+	dl, da, db := a.L - b.L, a.A - b.A, a.B - b.B
+	return dl * dl + da * da + db * db
+}
+
+// assignSeeds labels pixels with the closest seed searched within 2 steps around it,
+// compactness 0 measures position only (voronoi)
+func assignSeeds(labs []Lab, width, height int, seeds []seed, step, compactness float64) []int {
+	owners := make([]int, width * height)
+	distances := make([]float64, width * height)
+	for i := range owners {
+		owners[i], distances[i] = -1, math.Inf(1)
+	}
+
+	weight := compactness * compactness / (step * step)
+	for i, s := range seeds {
+		for y := max(int(s.y - 2 * step), 0); y < min(int(s.y + 2 * step) + 1, height); y++ {
+			for x := max(int(s.x - 2 * step), 0); x < min(int(s.x + 2 * step) + 1, width); x++ {
+				dx, dy := float64(x) - s.x, float64(y) - s.y
+				d := dx * dx + dy * dy
+				if compactness > 0 {
+					d = labDistance2(labs[y * width + x], s.lab) + d * weight
+				}
+				if d < distances[y * width + x] {
+					owners[y * width + x], distances[y * width + x] = i, d
+				}
+			}
+		}
+	}
+	return owners
+}
+
+// updateSeeds moves every seed to the mean color and position of its pixels
+func updateSeeds(labs []Lab, width int, owners []int, seeds []seed) []seed {
+	sums := make([]seed, len(seeds))
+	counts := make([]int, len(seeds))
+	for p, i := range owners {
+		if i < 0 { continue }
+		sums[i].lab.L += labs[p].L
+		sums[i].lab.A += labs[p].A
+		sums[i].lab.B += labs[p].B
+		sums[i].x += float64(p % width)
+		sums[i].y += float64(p / width)
+		counts[i]++
+	}
+
+	updated := make([]seed, len(seeds))
+	for i, s := range sums {
+		if counts[i] == 0 {
+			updated[i] = seeds[i]
+			continue
+		}
+		n := float64(counts[i])
+		updated[i] = seed{ Lab{ s.lab.L / n, s.lab.A / n, s.lab.B / n }, s.x / n, s.y / n }
+	}
+	return updated
+}
+
+// connectRegions relabels connected pieces of every cluster as separate regions,
+// pieces smaller than minSize (and unassigned pixels) join the neighbour region found before them
+func connectRegions(owners []int, width, height, minSize int) ([]int, int) {
+	labels := make([]int, len(owners))
+	for i := range labels {
+		labels[i] = -1
+	}
+
+	count := 0
+	queue := make([]int, 0)
+	for start := range owners {
+		if labels[start] != -1 { continue }
+
+		// adjacent region labeled earlier, absorbs this piece when it's too small
+		adjacent := -1
+		sx, sy := start % width, start / width
+		if sx > 0 {
+			adjacent = labels[start - 1]
+		} else if sy > 0 {
+			adjacent = labels[start - width]
+		}
+
+		queue = append(queue[:0], start)
+		labels[start] = count
+		for i := 0; i < len(queue); i++ {
+			p := queue[i]
+			x, y := p % width, p / width
+			for _, n := range [4][2]int{ { x - 1, y }, { x + 1, y }, { x, y - 1 }, { x, y + 1 } } {
+				if n[0] < 0 || n[1] < 0 || n[0] >= width || n[1] >= height { continue }
+				q := n[1] * width + n[0]
+				if labels[q] == -1 && owners[q] == owners[start] {
+					labels[q] = count
+					queue = append(queue, q)
+				}
+			}
+		}
+
+		if adjacent >= 0 && (len(queue) < minSize || owners[start] < 0) {
+			for _, p := range queue {
+				labels[p] = adjacent
+			}
+			continue
+		}
+		count++
+	}
+	return labels, count
+}
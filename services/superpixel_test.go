@@ -0,0 +1,65 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// splitImage is white on the left and black on the right of column split
+func splitImage(w, h, split int) *image.RGBA {
+	img := uniformImage(w, h, white)
+	for y := 0; y < h; y++ {
+		for x := split; x < w; x++ {
+			img.Set(x, y, black)
+		}
+	}
+	return img
+}
+
+func TestSuperpixels_SlicFollowsEdge(t *testing.T) {
+	assert := assert.New(t)
+	src := splitImage(60, 40, 25)
+	config := cmd.Config{ GridRows: 4, GridCols: 6, Tessellation: cmd.SLIC }
+
+	regions := Superpixels(src, config)
+
+	assert.NotContains(regions.Owners, -1)
+	colors := shapeColors(src, regions.Owners, regions.Count)
+	for _, c := range colors {
+		// no region mixes both sides of the edge
+		assert.Contains([]color.RGBA{ white, black }, c)
+	}
+}
+
+func TestSuperpixels_Voronoi(t *testing.T) {
+	config := cmd.Config{ GridRows: 3, GridCols: 3, Tessellation: cmd.VORONOI }
+
+	regions := Superpixels(uniformImage(30, 30, white), config)
+
+	assert.NotContains(t, regions.Owners, -1)
+	assert.Equal(t, 9, regions.Count)
+}
+
+func TestConnectRegions_SplitsPieces(t *testing.T) {
+	owners := []int{
+		0, 1, 0,
+		0, 1, 0,
+	}
+
+	labels, count := connectRegions(owners, 3, 2, 0)
+
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []int{ 0, 1, 2, 0, 1, 2 }, labels)
+}
+
+func TestToLab(t *testing.T) {
+	lab := ToLab(white)
+
+	assert.InDelta(t, 100, lab.L, 0.01)
+	assert.InDelta(t, 0, lab.A, 0.01)
+	assert.InDelta(t, 0, lab.B, 0.01)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Swatch struct {
+	Name   string
+	Rows   int
+	Cols   int
+	Colors []color.RGBA
+}
+
+type swatchJSON struct {
+	Name   string   `json:"name"`
+	Rows   int      `json:"rows"`
+	Cols   int      `json:"cols"`
+	Colors []string `json:"colors"`
+}
+
+func HexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (s Swatch) Encode(format string) ([]byte, error) {
+	switch format {
+	case "hex":
+		var sb strings.Builder
+		for _, c := range s.Colors {
+			sb.WriteString(HexColor(c))
+			sb.WriteString("\n")
+		}
+		return []byte(sb.String()), nil
+	case "gpl":
+		var sb strings.Builder
+		sb.WriteString("GIMP Palette\n")
+		sb.WriteString("Name: " + s.Name + "\n")
+		sb.WriteString(fmt.Sprintf("Columns: %d\n", s.Cols))
+		sb.WriteString("#\n")
+		for _, c := range s.Colors {
+			sb.WriteString(fmt.Sprintf("%3d %3d %3d\t%s\n", c.R, c.G, c.B, HexColor(c)))
+		}
+		return []byte(sb.String()), nil
+	case "json":
+		out := swatchJSON{ Name: s.Name, Rows: s.Rows, Cols: s.Cols, Colors: make([]string, len(s.Colors)) }
+		for i, c := range s.Colors {
+			out.Colors[i] = HexColor(c)
+		}
+		return json.MarshalIndent(out, "", "  ")
+	default:
+		return nil, errors.New("unknown swatch format: " + format)
+	}
+}
+
+// DecodeSwatch reads colors written by Encode, json also accepts a plain list of hex strings
+func DecodeSwatch(data []byte, format string) (Swatch, error) {
+	var s Swatch
+	switch format {
+	case "hex":
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" { continue }
+			c, err := cmd.ParseHexColor(line)
+			if err != nil {
+				return s, err
+			}
+			s.Colors = append(s.Colors, c)
+		}
+	case "gpl":
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "GIMP Palette") {
+				continue
+			}
+			if name, ok := strings.CutPrefix(line, "Name:"); ok {
+				s.Name = strings.TrimSpace(name)
+				continue
+			}
+			if strings.HasPrefix(line, "Columns:") { continue }
+			var r, g, b uint8
+			if _, err := fmt.Sscan(line, &r, &g, &b); err != nil {
+				return s, errors.New("invalid gpl color line: " + line)
+			}
+			s.Colors = append(s.Colors, color.RGBA{ r, g, b, 255 })
+		}
+	case "json":
+		var in swatchJSON
+		if err := json.Unmarshal(data, &in); err != nil {
+			if err = json.Unmarshal(data, &in.Colors); err != nil {
+				return s, err
+			}
+		}
+		s.Name, s.Rows, s.Cols = in.Name, in.Rows, in.Cols
+		for _, h := range in.Colors {
+			c, err := cmd.ParseHexColor(h)
+			if err != nil {
+				return s, err
+			}
+			s.Colors = append(s.Colors, c)
+		}
+	default:
+		return s, errors.New("unknown swatch format: " + format)
+	}
+	return s, nil
+}
+
+// LoadSwatch picks format by extension, .gpl / .json, anything else is a hex list
+func LoadSwatch(path string) (Swatch, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Swatch{}, err
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if format != "gpl" && format != "json" {
+		format = "hex"
+	}
+	s, err := DecodeSwatch(data, format)
+	if err != nil {
+		return s, errors.New("palette " + path + ": " + err.Error())
+	}
+	if len(s.Colors) == 0 {
+		return s, errors.New("palette " + path + " has no colors")
+	}
+	return s, nil
+}
+
+func SaveSwatch(s Swatch, format, path string) error {
+	data, err := s.Encode(format)
+	if err != nil {
+		return err
+	}
+	if path == cmd.STDIO {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// makeSwatchPath replaces image extension, "photo.jpg" -> "photo-swatch.gpl"
+func makeSwatchPath(config cmd.Config, original, format string) string {
+	if config.OutputDir == cmd.STDIO {
+		return cmd.STDIO
+	}
+	if original == cmd.STDIO {
+		original = STDIN_NAME
+	}
+	name := strings.TrimSuffix(original, filepath.Ext(original))
+	if config.OutputDir != "" {
+		name = filepath.Join(config.OutputDir, filepath.Base(name))
+	}
+	return name + "-swatch." + format
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwatchEncode_Hex(t *testing.T) {
+	swatch := Swatch{ Name: "test", Rows: 1, Cols: 2, Colors: []color.RGBA{ { 255, 0, 16, 255 }, { 1, 2, 3, 255 } } }
+
+	got, err := swatch.Encode("hex")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "#ff0010\n#010203\n", string(got))
+}
+
+func TestMakeSwatchPath(t *testing.T) {
+	assert.Equal(t, "fold/file.name-swatch.gpl", makeSwatchPath(cmd.Config{}, "fold/file.name.jpg", "gpl"))
+	assert.Equal(t, "out/file.name-swatch.gpl", makeSwatchPath(cmd.Config{ OutputDir: "out" }, "fold/file.name.jpg", "gpl"))
+}
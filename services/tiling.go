@@ -0,0 +1,89 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/draw"
+)
+
+// ImageTiles splits image of given size into tiles according to config
+func ImageTiles(width, height int, config cmd.Config) []Tile {
+	if config.IsSquareGrid() {
+		return SquareTiles(width, height, squareCellSize(width, height, config))
+	}
+	if config.IsSizedGrid() {
+		return MakeSizedTiles(width, height, config.TileWidth, config.TileHeight)
+	}
+	return MakeTiles(width, height, config.GridRows, config.GridCols)
+}
+
+// OutputTiles maps input tiles onto output resolution
+func OutputTiles(inTiles []Tile, src image.Rectangle, width, height int, config cmd.Config) []Tile {
+	if config.IsSquareGrid() || config.IsSizedGrid() {
+		return ScaleTiles(inTiles, src.Dx(), src.Dy(), width, height)
+	}
+	return MakeTiles(width, height, config.GridRows, config.GridCols)
+}
+
+// squareCellSize is either given in pixels or fits SquareCount cells along the shorter side
+func squareCellSize(width, height int, config cmd.Config) int {
+	if config.SquareSize > 0 {
+		return min(config.SquareSize, width, height)
+	}
+	return max(1, min(width, height) / max(config.SquareCount, 1))
+}
+
+// SquareTiles covers as much of the image as possible with cell x cell tiles,
+// leftover pixels are split evenly between opposite edges
+func SquareTiles(width, height, cell int) []Tile {
+	cols, rows := width / cell, height / cell
+	offsetX, offsetY := (width - cols * cell) / 2, (height - rows * cell) / 2
+
+	tiles := make([]Tile, 0, rows * cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			y, x := offsetY + r * cell, offsetX + c * cell
+			tiles = append(tiles, Tile{ y, x, y + cell, x + cell })
+		}
+	}
+	return tiles
+}
+
+func ScaleTiles(tiles []Tile, fromW, fromH, toW, toH int) []Tile {
+	scaled := make([]Tile, len(tiles))
+	sx := func(x int) int { return x * toW / fromW }
+	sy := func(y int) int { return y * toH / fromH }
+	for i, t := range tiles {
+		scaled[i] = Tile{ sy(t.YStart), sx(t.XStart), sy(t.YEnd), sx(t.XEnd) }
+	}
+	return scaled
+}
+
+// SpriteTiles are 1px tiles, one per grid tile, in rows x cols image
+func SpriteTiles(rows, cols int) []Tile {
+	tiles := make([]Tile, 0, rows * cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			tiles = append(tiles, Tile{ r, c, r + 1, c + 1 })
+		}
+	}
+	return tiles
+}
+
+// PrepareImage crops leftover pixels of square grid when crop fit is set
+func PrepareImage(img image.Image, config cmd.Config) image.Image {
+	if !config.IsSquareGrid() || config.SquareFit != cmd.FIT_CROP {
+		return img
+	}
+	bounds := img.Bounds()
+	tiles := SquareTiles(bounds.Dx(), bounds.Dy(), squareCellSize(bounds.Dx(), bounds.Dy(), config))
+	if len(tiles) == 0 {
+		return img
+	}
+	first, last := tiles[0], tiles[len(tiles) - 1]
+	area := image.Rect(first.XStart, first.YStart, last.XEnd, last.YEnd).Add(bounds.Min)
+
+	cropped := image.NewRGBA(image.Rect(0, 0, area.Dx(), area.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), img, area.Min, draw.Src)
+	return cropped
+}
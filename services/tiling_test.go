@@ -0,0 +1,50 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSquareTiles_Centered(t *testing.T) {
+	assert := assert.New(t)
+
+	tiles := SquareTiles(105, 62, 20)
+
+	assert.Len(tiles, 5 * 3)
+	assert.Equal(Tile{ 1, 2, 21, 22 }, tiles[0])
+	assert.Equal(Tile{ 41, 82, 61, 102 }, tiles[len(tiles) - 1])
+}
+
+func TestImageTiles_SquareCount(t *testing.T) {
+	config := cmd.Config{ SquareCount: 4 }
+
+	tiles := ImageTiles(200, 100, config)
+	rows, cols := gridShape(tiles)
+
+	// 4 cells along the shorter side, 25px each
+	assert.Equal(t, 4, rows)
+	assert.Equal(t, 8, cols)
+	for _, tile := range tiles {
+		assert.Equal(t, tile.XEnd - tile.XStart, tile.YEnd - tile.YStart)
+	}
+}
+
+func TestPrepareImage_Crop(t *testing.T) {
+	src := uniformImage(105, 62, white)
+	config := cmd.Config{ SquareSize: 20, SquareFit: cmd.FIT_CROP }
+
+	cropped := PrepareImage(src, config)
+	tiles := ImageTiles(cropped.Bounds().Dx(), cropped.Bounds().Dy(), config)
+
+	assert.Equal(t, image.Rect(0, 0, 100, 60), cropped.Bounds())
+	assert.Equal(t, Tile{ 0, 0, 20, 20 }, tiles[0])
+}
+
+func TestScaleTiles(t *testing.T) {
+	scaled := ScaleTiles([]Tile{ { 10, 20, 30, 40 } }, 100, 100, 50, 200)
+
+	assert.Equal(t, []Tile{ { 20, 10, 60, 20 } }, scaled)
+}
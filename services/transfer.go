@@ -0,0 +1,134 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// labStats is mean and standard deviation of every Lab channel
+func labStats(img image.Image) (mean, deviation [3]float64) {
+	bounds := img.Bounds()
+	n := float64(bounds.Dx() * bounds.Dy())
+	if n == 0 {
+		return mean, deviation
+	}
+	var sum, sum2 [3]float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			lab := ToLab(img.At(x, y))
+			for i, v := range [3]float64{ lab.L, lab.A, lab.B } {
+				sum[i] += v
+				sum2[i] += v * v
+			}
+		}
+	}
+	for i := range mean {
+		mean[i] = sum[i] / n
+		deviation[i] = math.Sqrt(math.Max(sum2[i] / n - mean[i] * mean[i], 0))
+	}
+	return mean, deviation
+}
+
+// TransferReference is what transfer needs of the reference image, computed once for all inputs
+type TransferReference struct {
+	Mean, Deviation [3]float64
+	Palette []color.RGBA // palette transfer only
+}
+
+func NewTransferReference(ref image.Image, options cmd.ModeOptions) TransferReference {
+	var r TransferReference
+	if options.Transfer == cmd.PALETTE_TRANSFER {
+		r.Palette = QuantizePalette(ref, max(options.Colors, 1))
+	} else {
+		r.Mean, r.Deviation = labStats(ref)
+	}
+	return r
+}
+
+// ReinhardTransfer shifts and scales Lab channels of src so their mean and deviation match ref
+func ReinhardTransfer(src, ref image.Image, dst *image.RGBA) {
+	refMean, refDev := labStats(ref)
+	reinhardTransfer(src, refMean, refDev, dst)
+}
+
+func reinhardTransfer(src image.Image, refMean, refDev [3]float64, dst *image.RGBA) {
+	srcMean, srcDev := labStats(src)
+	var scale [3]float64
+	for i := range scale {
+		scale[i] = 1
+		if srcDev[i] > 0 {
+			scale[i] = refDev[i] / srcDev[i]
+		}
+	}
+
+	bounds := src.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			lab := ToLab(src.At(bounds.Min.X + x, bounds.Min.Y + y))
+			dst.SetRGBA(x, y, FromLab(Lab{
+				(lab.L - srcMean[0]) * scale[0] + refMean[0],
+				(lab.A - srcMean[1]) * scale[1] + refMean[1],
+				(lab.B - srcMean[2]) * scale[2] + refMean[2],
+			}))
+		}
+	}
+}
+
+// PaletteTransfer extracts n colors from both images, pairs them by lightness
+// and moves every pixel by the difference of its near source colors and their paired reference colors
+func PaletteTransfer(src, ref image.Image, n int, dst *image.RGBA) {
+	paletteTransfer(src, QuantizePalette(ref, n), n, dst)
+}
+
+func paletteTransfer(src image.Image, refPalette []color.RGBA, n int, dst *image.RGBA) {
+	srcPalette := QuantizePalette(src, n)
+	if len(srcPalette) == 0 || len(refPalette) == 0 {
+		return
+	}
+	byLightness := func(p []color.RGBA) {
+		sort.SliceStable(p, func(a, b int) bool { return luminance(p[a]) < luminance(p[b]) })
+	}
+	byLightness(srcPalette)
+	byLightness(refPalette)
+
+	srcLabs := make([]Lab, len(srcPalette))
+	shifts := make([]Lab, len(srcPalette))
+	for i, c := range srcPalette {
+		// palettes may differ in size when an image has few colors
+		target := refPalette[i * len(refPalette) / len(srcPalette)]
+		srcLabs[i] = ToLab(c)
+		t := ToLab(target)
+		shifts[i] = Lab{ t.L - srcLabs[i].L, t.A - srcLabs[i].A, t.B - srcLabs[i].B }
+	}
+
+	bounds := src.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			lab := ToLab(src.At(bounds.Min.X + x, bounds.Min.Y + y))
+			// inverse distance weights blend shifts of near colors, hard nearest pick leaves seams
+			var shift Lab
+			total := 0.0
+			for i, p := range srcLabs {
+				w := 1 / math.Pow(labDistance2(lab, p) + 1, 2)
+				shift.L, shift.A, shift.B = shift.L + shifts[i].L * w, shift.A + shifts[i].A * w, shift.B + shifts[i].B * w
+				total += w
+			}
+			dst.SetRGBA(x, y, FromLab(Lab{ lab.L + shift.L / total, lab.A + shift.A / total, lab.B + shift.B / total }))
+		}
+	}
+}
+
+// TransferPainter recolors source to look like ref
+func TransferPainter(ref TransferReference, options cmd.ModeOptions) PaintFunc {
+	return func(src image.Image, _ []Tile, dst *image.RGBA, _ []Tile) image.Image {
+		if options.Transfer == cmd.PALETTE_TRANSFER {
+			paletteTransfer(src, ref.Palette, max(options.Colors, 1), dst)
+		} else {
+			reinhardTransfer(src, ref.Mean, ref.Deviation, dst)
+		}
+		return dst
+	}
+}
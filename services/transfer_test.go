@@ -0,0 +1,61 @@
+package services
+
+import (
+	"color-pallete/cmd"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromLab_RoundTrip(t *testing.T) {
+	for _, c := range []color.RGBA{ white, black, gray, { 200, 30, 90, 255 } } {
+		assert.Equal(t, c, FromLab(ToLab(c)))
+	}
+}
+
+func TestReinhardTransfer_MatchesStatistics(t *testing.T) {
+	assert := assert.New(t)
+	src := splitImage(20, 10, 10)
+	ref := uniformImage(20, 10, color.RGBA{ 200, 30, 90, 255 })
+	dst := image.NewRGBA(src.Bounds())
+
+	ReinhardTransfer(src, ref, dst)
+
+	mean, deviation := labStats(dst)
+	refMean, _ := labStats(ref)
+	// flat reference has no deviation, everything becomes its color
+	for i := range mean {
+		assert.InDelta(refMean[i], mean[i], 0.5)
+		assert.InDelta(0, deviation[i], 0.5)
+	}
+}
+
+func TestPaletteTransfer_PairsByLightness(t *testing.T) {
+	src := splitImage(20, 10, 10)
+	dark, light := color.RGBA{ 20, 20, 120, 255 }, color.RGBA{ 250, 220, 120, 255 }
+	ref := uniformImage(20, 10, light)
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 5; x++ {
+			ref.Set(x, y, dark)
+		}
+	}
+	dst := image.NewRGBA(src.Bounds())
+
+	PaletteTransfer(src, ref, 2, dst)
+
+	// white side takes the light color, black side the dark one
+	assert.Equal(t, light, dst.RGBAAt(0, 0))
+	assert.Equal(t, dark, dst.RGBAAt(19, 9))
+}
+
+func TestTransferPainter_Reinhard(t *testing.T) {
+	ref := uniformImage(4, 4, gray)
+	src := uniformImage(4, 4, white)
+
+	options := cmd.ModeOptions{ Transfer: cmd.REINHARD }
+	out := TransferPainter(NewTransferReference(ref, options), options)(src, nil, image.NewRGBA(src.Bounds()), nil)
+
+	assert.Equal(t, gray, out.At(2, 2))
+}
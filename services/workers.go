@@ -0,0 +1,14 @@
+package services
+
+// workers caps number of files processed at once
+type workers chan struct{}
+
+func newWorkers(count int) workers {
+	return make(workers, max(count, 1))
+}
+
+func (w workers) run(fn func()) {
+	w <- struct{}{}
+	defer func() { <-w }()
+	fn()
+}